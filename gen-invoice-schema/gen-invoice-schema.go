@@ -5,11 +5,16 @@ import (
 	"log"
 	"os"
 	"path/filepath"
+	"reflect"
 
 	"github.com/domonda/api/golang/domonda"
+	"github.com/domonda/go-types/money"
+	"github.com/domonda/go-types/nullable"
 	"github.com/invopop/jsonschema"
 )
 
+const schemaBaseURL = "https://raw.githubusercontent.com/domonda/api/refs/heads/master/"
+
 func main() {
 	// ------------------------------------------------------------------------
 	// Configure the jsonschema.Reflector
@@ -24,28 +29,51 @@ func main() {
 		Anonymous:      true,
 		ExpandedStruct: true,
 		DoNotReference: true,
+		Mapper:         mapType,
 	}
 	err = reflector.AddGoComments("github.com/domonda/api/golang/domonda", ".")
 	if err != nil {
 		log.Fatalf("Failed to parse Go comments: %v", err)
 	}
 
+	// Master data struct fields don't use omitempty JSON tags,
+	// so required fields are marked with `jsonschema:"required"` tags
+	masterDataReflector := *reflector
+	masterDataReflector.RequiredFromJSONSchemaTags = true
+
 	// ------------------------------------------------------------------------
-	// Generate the schema
+	// Generate and write the schemas
 	// ------------------------------------------------------------------------
 
-	schema := reflector.Reflect(domonda.Invoice{})
-	schema.ID = "https://raw.githubusercontent.com/domonda/api/refs/heads/master/invoice.schema.json"
+	writeSchema(reflector, domonda.Invoice{}, "invoice.schema.json")
+
+	writeSchema(&masterDataReflector, domonda.Partner{}, "partner.schema.json")
+	writeSchema(&masterDataReflector, domonda.GLAccount{}, "glaccount.schema.json")
+	writeSchema(&masterDataReflector, domonda.BankAccount{}, "bankaccount.schema.json")
+	writeSchema(&masterDataReflector, domonda.RealEstateObject{}, "realestateobject.schema.json")
+	writeSchema(&masterDataReflector, domonda.ObjectTenantOwner{}, "objecttenantowner.schema.json")
+
+	writeSchema(&masterDataReflector, domonda.ImportPartnerResult{}, "importpartnerresult.schema.json")
+	writeSchema(&masterDataReflector, domonda.ImportGLAccountResult{}, "importglaccountresult.schema.json")
+	writeSchema(&masterDataReflector, domonda.ImportBankAccountResult{}, "importbankaccountresult.schema.json")
+	writeSchema(&masterDataReflector, domonda.ImportRealEstateObjectResult{}, "importrealestateobjectresult.schema.json")
+	writeSchema(&masterDataReflector, domonda.ImportObjectTenantOwnerResult{}, "importobjecttenantownerresult.schema.json")
+	writeSchema(&masterDataReflector, domonda.ImportObjectInstanceResult{}, "importobjectinstanceresult.schema.json")
+
+	writeOpenAPI(reflector, &masterDataReflector, "openapi.json")
+}
+
+// writeSchema reflects the type of value and writes
+// the resulting JSON schema to fileName in the repository root directory.
+func writeSchema(reflector *jsonschema.Reflector, value any, fileName string) {
+	schema := reflector.Reflect(value)
+	schema.ID = jsonschema.ID(schemaBaseURL + fileName)
 	schemaJSON, err := json.MarshalIndent(schema, "", "  ")
 	if err != nil {
-		log.Fatalf("Failed to generate schema: %v", err)
+		log.Fatalf("Failed to generate schema %s: %v", fileName, err)
 	}
 
-	// ------------------------------------------------------------------------
-	// Write the schema to a file
-	// ------------------------------------------------------------------------
-
-	filePath, err := filepath.Abs("../../invoice.schema.json")
+	filePath, err := filepath.Abs(filepath.Join("../..", fileName))
 	if err != nil {
 		log.Fatalf("Failed to get absolute path: %v", err)
 	}
@@ -53,5 +81,56 @@ func main() {
 	if err != nil {
 		log.Fatalf("Failed to write schema: %v", err)
 	}
-	log.Println("Invoice schema written to", filePath)
+	log.Printf("%T schema written to %s", value, filePath)
+}
+
+// enumType is implemented by the enum types generated by go-enum
+type enumType interface {
+	EnumStrings() []string
+}
+
+// mapType returns schemas for types that don't implement
+// a JSONSchema method themselves or nil to use the default reflection.
+func mapType(t reflect.Type) *jsonschema.Schema {
+	switch t {
+	case reflect.TypeFor[money.Currency]():
+		return currencySchema()
+
+	case reflect.TypeFor[money.NullableCurrency]():
+		return &jsonschema.Schema{
+			OneOf: []*jsonschema.Schema{
+				currencySchema(),
+				{Type: "null"},
+			},
+			Title: "Nullable ISO 4217 Currency Code",
+		}
+
+	case reflect.TypeFor[nullable.StringArray]():
+		return &jsonschema.Schema{
+			OneOf: []*jsonschema.Schema{
+				{Type: "array", Items: &jsonschema.Schema{Type: "string"}},
+				{Type: "null"},
+			},
+			Title: "Nullable String Array",
+		}
+	}
+
+	if t.Kind() == reflect.String && t.Implements(reflect.TypeFor[enumType]()) {
+		enums := reflect.Zero(t).Interface().(enumType).EnumStrings()
+		schema := &jsonschema.Schema{Type: "string"}
+		for _, e := range enums {
+			schema.Enum = append(schema.Enum, e)
+		}
+		return schema
+	}
+
+	return nil
+}
+
+func currencySchema() *jsonschema.Schema {
+	return &jsonschema.Schema{
+		Type:    "string",
+		Pattern: "^[A-Z]{3}$",
+		Title:   "ISO 4217 Currency Code",
+	}
 }
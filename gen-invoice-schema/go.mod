@@ -6,7 +6,10 @@ replace github.com/domonda/api/golang/domonda => ../golang/domonda
 
 require github.com/domonda/api/golang/domonda v0.0.0-00010101000000-000000000000 // replaced
 
-require github.com/invopop/jsonschema v0.13.0
+require (
+	github.com/domonda/go-types v0.0.0-20260327082518-11ac2cfe4cdf
+	github.com/invopop/jsonschema v0.13.0
+)
 
 require (
 	github.com/bahlo/generic-list-go v0.2.0 // indirect
@@ -18,7 +21,6 @@ require (
 	github.com/davecgh/go-spew v1.1.1 // indirect
 	github.com/domonda/go-errs v1.0.1 // indirect
 	github.com/domonda/go-pretty v1.0.0 // indirect
-	github.com/domonda/go-types v0.0.0-20260327082518-11ac2cfe4cdf // indirect
 	github.com/fatih/color v1.19.0 // indirect
 	github.com/fsnotify/fsnotify v1.9.0 // indirect
 	github.com/goccy/go-json v0.10.6 // indirect
@@ -0,0 +1,507 @@
+package main
+
+import (
+	"encoding/json"
+	"log"
+	"os"
+	"path/filepath"
+
+	"github.com/domonda/api/golang/domonda"
+	"github.com/invopop/jsonschema"
+)
+
+// OpenAPI 3.1 document types, only the parts used by writeOpenAPI are defined.
+// OpenAPI 3.1 uses JSON Schema 2020-12 so jsonschema.Schema can be used directly.
+
+type openAPIDocument struct {
+	OpenAPI    string                                  `json:"openapi"`
+	Info       openAPIInfo                             `json:"info"`
+	Servers    []openAPIServer                         `json:"servers"`
+	Security   []map[string][]string                   `json:"security"`
+	Paths      map[string]map[string]*openAPIOperation `json:"paths"`
+	Components openAPIComponents                       `json:"components"`
+}
+
+type openAPIInfo struct {
+	Title       string `json:"title"`
+	Description string `json:"description,omitempty"`
+	Version     string `json:"version"`
+}
+
+type openAPIServer struct {
+	URL string `json:"url"`
+}
+
+type openAPIComponents struct {
+	Schemas         map[string]*jsonschema.Schema     `json:"schemas"`
+	SecuritySchemes map[string]*openAPISecurityScheme `json:"securitySchemes"`
+}
+
+type openAPISecurityScheme struct {
+	Type        string `json:"type"`
+	Scheme      string `json:"scheme"`
+	Description string `json:"description,omitempty"`
+}
+
+type openAPIOperation struct {
+	OperationID  string                      `json:"operationId"`
+	Summary      string                      `json:"summary"`
+	Description  string                      `json:"description,omitempty"`
+	Tags         []string                    `json:"tags,omitempty"`
+	ExternalDocs *openAPIExternalDocs        `json:"externalDocs,omitempty"`
+	Parameters   []*openAPIParameter         `json:"parameters,omitempty"`
+	RequestBody  *openAPIRequestBody         `json:"requestBody,omitempty"`
+	Responses    map[string]*openAPIResponse `json:"responses"`
+}
+
+type openAPIExternalDocs struct {
+	Description string `json:"description,omitempty"`
+	URL         string `json:"url"`
+}
+
+type openAPIParameter struct {
+	Name        string             `json:"name"`
+	In          string             `json:"in"`
+	Description string             `json:"description,omitempty"`
+	Required    bool               `json:"required,omitempty"`
+	Schema      *jsonschema.Schema `json:"schema"`
+}
+
+type openAPIRequestBody struct {
+	Required bool                         `json:"required"`
+	Content  map[string]*openAPIMediaType `json:"content"`
+}
+
+type openAPIResponse struct {
+	Description string                       `json:"description"`
+	Content     map[string]*openAPIMediaType `json:"content,omitempty"`
+}
+
+type openAPIMediaType struct {
+	Schema   *jsonschema.Schema         `json:"schema"`
+	Encoding map[string]openAPIEncoding `json:"encoding,omitempty"`
+}
+
+type openAPIEncoding struct {
+	ContentType string `json:"contentType"`
+}
+
+// writeOpenAPI writes an OpenAPI 3.1 document describing the REST API
+// to fileName in the repository root directory.
+// Request and response bodies are reflected from the Go SDK types,
+// the endpoint metadata like query parameters is defined here
+// and has to be kept in sync with the Go functions of the SDK.
+func writeOpenAPI(invoiceReflector, masterDataReflector *jsonschema.Reflector, fileName string) {
+	componentSchema := func(reflector *jsonschema.Reflector, value any) *jsonschema.Schema {
+		schema := reflector.Reflect(value)
+		schema.Version = ""
+		return schema
+	}
+	doc := &openAPIDocument{
+		OpenAPI: "3.1.0",
+		Info: openAPIInfo{
+			Title:       "Domonda REST API",
+			Description: "REST API for file uploads, downloads, and bulk master data imports. Generated from the Go SDK github.com/domonda/api/golang/domonda",
+			Version:     "1.0.0",
+		},
+		Servers:  []openAPIServer{{URL: domonda.BaseURL}},
+		Security: []map[string][]string{{"bearerAuth": {}}},
+		Components: openAPIComponents{
+			Schemas: map[string]*jsonschema.Schema{
+				"Invoice":                       componentSchema(invoiceReflector, domonda.Invoice{}),
+				"Partner":                       componentSchema(masterDataReflector, domonda.Partner{}),
+				"GLAccount":                     componentSchema(masterDataReflector, domonda.GLAccount{}),
+				"BankAccount":                   componentSchema(masterDataReflector, domonda.BankAccount{}),
+				"RealEstateObject":              componentSchema(masterDataReflector, domonda.RealEstateObject{}),
+				"ObjectTenantOwner":             componentSchema(masterDataReflector, domonda.ObjectTenantOwner{}),
+				"ImportPartnerResult":           componentSchema(masterDataReflector, domonda.ImportPartnerResult{}),
+				"ImportGLAccountResult":         componentSchema(masterDataReflector, domonda.ImportGLAccountResult{}),
+				"ImportBankAccountResult":       componentSchema(masterDataReflector, domonda.ImportBankAccountResult{}),
+				"ImportRealEstateObjectResult":  componentSchema(masterDataReflector, domonda.ImportRealEstateObjectResult{}),
+				"ImportObjectTenantOwnerResult": componentSchema(masterDataReflector, domonda.ImportObjectTenantOwnerResult{}),
+				"ImportObjectInstanceResult":    componentSchema(masterDataReflector, domonda.ImportObjectInstanceResult{}),
+			},
+			SecuritySchemes: map[string]*openAPISecurityScheme{
+				"bearerAuth": {
+					Type:        "http",
+					Scheme:      "bearer",
+					Description: "API key of a client company",
+				},
+			},
+		},
+		Paths: map[string]map[string]*openAPIOperation{
+			"/upload": {
+				"post": uploadOperation(),
+			},
+			"/document/{documentID}.pdf": {
+				"get": documentPDFOperation(),
+			},
+			"/document/{documentID}/custom-fields/": {
+				"get": documentCustomFieldsOperation(),
+			},
+			"/masterdata/partner-companies": {
+				"post": masterDataOperation(
+					"PostPartners",
+					"Upsert partner companies",
+					"Existing partner companies are identified by VATIDNo, VendorAccountNumber, ClientAccountNumber, and Name and then updated. Else, a new partner company is created.",
+					"Partner",
+					"ImportPartnerResult",
+					failOnInvalidParam, useCleanedInvalidParam, allOrNoneParam, sourceParam,
+				),
+			},
+			"/masterdata/gl-accounts": {
+				"post": masterDataOperation(
+					"PostGLAccounts",
+					"Upsert general ledger accounts",
+					"Existing general ledger accounts are identified by their number and optionally by their name and then updated. Else, a new account is created.",
+					"GLAccount",
+					"ImportGLAccountResult",
+					findByNameParam, objectSpecificAccountNosParam, failOnInvalidParam, useCleanedInvalidParam, allOrNoneParam, sourceParam,
+				),
+			},
+			"/masterdata/bank-accounts": {
+				"post": masterDataOperation(
+					"PostBankAccounts",
+					"Upsert bank accounts",
+					"Existing bank accounts are identified by their IBAN and then updated instead of inserted.",
+					"BankAccount",
+					"ImportBankAccountResult",
+					failOnInvalidParam, allOrNoneParam, sourceParam,
+				),
+			},
+			"/masterdata/real-estate-objects": {
+				"post": masterDataOperation(
+					"PostRealEstateObjects",
+					"Upsert real estate objects",
+					"Objects are identified by their Number. If an object with the same number exists it will be updated, otherwise a new object is created.",
+					"RealEstateObject",
+					"ImportRealEstateObjectResult",
+					sourceParam,
+				),
+			},
+			"/masterdata/real-estate-object-tenant-owners": {
+				"post": masterDataOperation(
+					"PostObjectTenantOwners",
+					"Upsert tenant owners of real estate objects",
+					"",
+					"ObjectTenantOwner",
+					"ImportObjectTenantOwnerResult",
+					sourceParam,
+				),
+			},
+			"/masterdata/upsert-objects/{className}/id-prop/{idPropName}": {
+				"post": upsertObjectsOperation(),
+			},
+			"/idwell/crm-ticket/": {
+				"put": idwellCRMTicketOperation(),
+			},
+		},
+	}
+
+	docJSON, err := json.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		log.Fatalf("Failed to generate OpenAPI document: %v", err)
+	}
+	filePath, err := filepath.Abs(filepath.Join("../..", fileName))
+	if err != nil {
+		log.Fatalf("Failed to get absolute path: %v", err)
+	}
+	err = os.WriteFile(filePath, docJSON, 0644)
+	if err != nil {
+		log.Fatalf("Failed to write OpenAPI document: %v", err)
+	}
+	log.Println("OpenAPI document written to", filePath)
+}
+
+// ----------------------------------------------------------------------------
+// Parameters
+// ----------------------------------------------------------------------------
+
+var (
+	sourceParam = &openAPIParameter{
+		Name:        "source",
+		In:          "query",
+		Description: "Optional name or ID of who did the import, use your company or service name. Pass \"" + domonda.SourceTestEndpointNOP + "\" to test the endpoint without side effects.",
+		Schema:      &jsonschema.Schema{Type: "string"},
+	}
+	failOnInvalidParam = boolQueryParam(
+		"failOnInvalid",
+		"Fail if any item data is invalid (interacts with useCleanedInvalid)",
+	)
+	useCleanedInvalidParam = boolQueryParam(
+		"useCleanedInvalid",
+		"Clean invalid data and import what's valid (only effective when failOnInvalid is false)",
+	)
+	allOrNoneParam = boolQueryParam(
+		"allOrNone",
+		"Import either all items or none in case of any error using a database transaction",
+	)
+	findByNameParam = boolQueryParam(
+		"findByName",
+		"Find existing general ledger accounts case-insensitive by name if not found by number",
+	)
+	objectSpecificAccountNosParam = boolQueryParam(
+		"objectSpecificAccountNos",
+		"Append the object numbers to the account numbers to make them unique per real estate object",
+	)
+	documentIDParam = &openAPIParameter{
+		Name:     "documentID",
+		In:       "path",
+		Required: true,
+		Schema:   &jsonschema.Schema{Type: "string", Format: "uuid"},
+	}
+)
+
+func boolQueryParam(name, description string) *openAPIParameter {
+	return &openAPIParameter{
+		Name:        name,
+		In:          "query",
+		Description: description,
+		Schema:      &jsonschema.Schema{Type: "boolean", Default: false},
+	}
+}
+
+// ----------------------------------------------------------------------------
+// Operations
+// ----------------------------------------------------------------------------
+
+func goFunctionDocs(funcName string) *openAPIExternalDocs {
+	return &openAPIExternalDocs{
+		Description: "Go SDK function " + funcName,
+		URL:         "https://pkg.go.dev/github.com/domonda/api/golang/domonda#" + funcName,
+	}
+}
+
+func componentRef(name string) *jsonschema.Schema {
+	return &jsonschema.Schema{Ref: "#/components/schemas/" + name}
+}
+
+func jsonContent(schema *jsonschema.Schema) map[string]*openAPIMediaType {
+	return map[string]*openAPIMediaType{
+		"application/json": {Schema: schema},
+	}
+}
+
+func errorResponses(responses map[string]*openAPIResponse) map[string]*openAPIResponse {
+	textContent := map[string]*openAPIMediaType{
+		"text/plain": {Schema: &jsonschema.Schema{Type: "string"}},
+	}
+	for status, description := range map[string]string{
+		"400": "The request contains invalid data",
+		"401": "Invalid API key",
+		"402": "The client company is not active or was blocked because of missing payments",
+		"500": "Something unexpected went wrong",
+	} {
+		if _, exists := responses[status]; !exists {
+			responses[status] = &openAPIResponse{Description: description, Content: textContent}
+		}
+	}
+	return responses
+}
+
+func masterDataOperation(funcName, summary, description, itemSchema, resultSchema string, params ...*openAPIParameter) *openAPIOperation {
+	success := &openAPIResponse{Description: "Import finished"}
+	if resultSchema != "" {
+		success.Description = "One import result per item of the request body in the same order"
+		success.Content = jsonContent(&jsonschema.Schema{Type: "array", Items: componentRef(resultSchema)})
+	}
+	return &openAPIOperation{
+		OperationID:  funcName,
+		Summary:      summary,
+		Description:  description,
+		Tags:         []string{"masterdata"},
+		ExternalDocs: goFunctionDocs(funcName),
+		Parameters:   params,
+		RequestBody: &openAPIRequestBody{
+			Required: true,
+			Content:  jsonContent(&jsonschema.Schema{Type: "array", Items: componentRef(itemSchema)}),
+		},
+		Responses: errorResponses(map[string]*openAPIResponse{"200": success}),
+	}
+}
+
+func upsertObjectsOperation() *openAPIOperation {
+	identifier := &jsonschema.Schema{Type: "string", Pattern: "^[a-zA-Z0-9_-]+$"}
+	return &openAPIOperation{
+		OperationID:  "PostObjectInstancesWithIDProp",
+		Summary:      "Upsert instances of a custom object class",
+		Description:  "Updates or inserts instances of the class className using the property idPropName as the identifier for the objects.",
+		Tags:         []string{"masterdata"},
+		ExternalDocs: goFunctionDocs("PostObjectInstancesWithIDProp"),
+		Parameters: []*openAPIParameter{
+			{Name: "className", In: "path", Required: true, Description: "Name of the custom object class", Schema: identifier},
+			{Name: "idPropName", In: "path", Required: true, Description: "Name of the property used as identifier, must be present in every object", Schema: identifier},
+			sourceParam,
+		},
+		RequestBody: &openAPIRequestBody{
+			Required: true,
+			Content: jsonContent(&jsonschema.Schema{
+				Type:  "array",
+				Items: &jsonschema.Schema{Type: "object", Description: "Property names of the class mapped to their values"},
+			}),
+		},
+		Responses: errorResponses(map[string]*openAPIResponse{
+			"200": {
+				Description: "One import result per object of the request body in the same order",
+				Content:     jsonContent(&jsonschema.Schema{Type: "array", Items: componentRef("ImportObjectInstanceResult")}),
+			},
+		}),
+	}
+}
+
+func uploadOperation() *openAPIOperation {
+	str := func(description string) *jsonschema.Schema {
+		return &jsonschema.Schema{Type: "string", Description: description}
+	}
+	file := func(description string) *jsonschema.Schema {
+		return &jsonschema.Schema{Type: "string", ContentMediaType: "application/octet-stream", Description: description}
+	}
+	boolean := func(description string) *jsonschema.Schema {
+		return &jsonschema.Schema{Type: "boolean", Default: false, Description: description}
+	}
+	form := &jsonschema.Schema{
+		Type:       "object",
+		Required:   []string{"document"},
+		Properties: jsonschema.NewProperties(),
+	}
+	form.Properties.Set("document", file("Visual representation of the document as PDF, PNG, JPEG, or TIFF"))
+	form.Properties.Set("documentCategory", &jsonschema.Schema{Type: "string", Format: "uuid", Description: "ID of the document category, alternatively use documentType, bookingType, and bookingCategory"})
+	form.Properties.Set("documentType", &jsonschema.Schema{
+		Type: "string",
+		Enum: []any{
+			"INCOMING_INVOICE",
+			"OUTGOING_INVOICE",
+			"INCOMING_DUNNING_LETTER",
+			"OUTGOING_DUNNING_LETTER",
+			"INCOMING_DELIVERY_NOTE",
+			"OUTGOING_DELIVERY_NOTE",
+			"BANK_STATEMENT",
+			"CREDITCARD_STATEMENT",
+			"FACTORING_STATEMENT",
+			"OTHER_DOCUMENT",
+		},
+		Description: "Document type of the document category if documentCategory is not provided",
+	})
+	form.Properties.Set("bookingType", &jsonschema.Schema{
+		Type:        "string",
+		Enum:        []any{"", "CASH_BOOK", "CLEARING_ACCOUNT"},
+		Description: "Booking type of the document category if documentCategory is not provided",
+	})
+	form.Properties.Set("bookingCategory", str("Booking category of the document category if documentCategory is not provided"))
+	form.Properties.Set("invoice", componentRef("Invoice"))
+	form.Properties.Set("ebInterface", file("Invoice as ebInterface 5.0 XML"))
+	form.Properties.Set("tag", &jsonschema.Schema{Type: "array", Items: &jsonschema.Schema{Type: "string"}, Description: "Tags to add to the document"})
+	form.Properties.Set("uuid", &jsonschema.Schema{Type: "string", Format: "uuid", Description: "User-defined ID for the document that must not exist yet"})
+	form.Properties.Set("waitForExtraction", boolean("Extract the invoice data synchronously before returning"))
+	form.Properties.Set("allowDuplicateDeleted", boolean("Allow uploading a duplicate of an already deleted document"))
+
+	duplicateDetail := &jsonschema.Schema{Type: "object", Properties: jsonschema.NewProperties()}
+	duplicateDetail.Properties.Set("documentFileHash", &jsonschema.Schema{Type: "string"})
+	duplicateDetail.Properties.Set("duplicateDocumentIDs", &jsonschema.Schema{Type: "array", Items: &jsonschema.Schema{Type: "string", Format: "uuid"}})
+	duplicateDetail.Properties.Set("processingFileName", &jsonschema.Schema{Type: "string"})
+	duplicate := &jsonschema.Schema{Type: "object", Properties: jsonschema.NewProperties()}
+	duplicate.Properties.Set("error", &jsonschema.Schema{Type: "string"})
+	duplicate.Properties.Set("detail", duplicateDetail)
+
+	return &openAPIOperation{
+		OperationID:  "UploadDocument",
+		Summary:      "Upload a document",
+		Description:  "Uploads a document file with optional structured invoice data. Basic document processing is done synchronously and may take up to 5 seconds per page.",
+		Tags:         []string{"documents"},
+		ExternalDocs: goFunctionDocs("UploadDocument"),
+		RequestBody: &openAPIRequestBody{
+			Required: true,
+			Content: map[string]*openAPIMediaType{
+				"multipart/form-data": {
+					Schema: form,
+					Encoding: map[string]openAPIEncoding{
+						"invoice":     {ContentType: "application/json"},
+						"ebInterface": {ContentType: "application/xml"},
+					},
+				},
+			},
+		},
+		Responses: errorResponses(map[string]*openAPIResponse{
+			"200": {
+				Description: "ID of the created document",
+				Content: map[string]*openAPIMediaType{
+					"text/plain": {Schema: &jsonschema.Schema{Type: "string", Format: "uuid"}},
+				},
+			},
+			"409": {
+				Description: "A document with the same UUID or file contents already exists",
+				Content:     jsonContent(duplicate),
+			},
+		}),
+	}
+}
+
+func documentPDFOperation() *openAPIOperation {
+	return &openAPIOperation{
+		OperationID: "GetDocumentPDF",
+		Summary:     "Download the PDF of a document",
+		Tags:        []string{"documents"},
+		Parameters: []*openAPIParameter{
+			documentIDParam,
+			{
+				Name:        "auditTrail",
+				In:          "query",
+				Description: "Add the audit trail to the PDF or download only the audit trail",
+				Schema:      &jsonschema.Schema{Type: "string", Enum: []any{"append", "prepend", "configured", "only"}},
+			},
+			{
+				Name:        "auditTrailLang",
+				In:          "query",
+				Description: "Language code of the audit trail, default is German",
+				Schema:      &jsonschema.Schema{Type: "string"},
+			},
+			{
+				Name:        "embedXML",
+				In:          "query",
+				Description: "Pass 1 to embed the UN/CEFACT format XML into the PDF",
+				Schema:      &jsonschema.Schema{Type: "string", Enum: []any{"1"}},
+			},
+		},
+		Responses: errorResponses(map[string]*openAPIResponse{
+			"200": {
+				Description: "PDF file of the document",
+				Content: map[string]*openAPIMediaType{
+					"application/pdf": {Schema: &jsonschema.Schema{Type: "string", ContentMediaType: "application/pdf"}},
+				},
+			},
+		}),
+	}
+}
+
+func documentCustomFieldsOperation() *openAPIOperation {
+	field := &jsonschema.Schema{Type: "object", Properties: jsonschema.NewProperties()}
+	field.Properties.Set("name", &jsonschema.Schema{Type: "string"})
+	field.Properties.Set("value", &jsonschema.Schema{Type: "string"})
+	return &openAPIOperation{
+		OperationID: "GetDocumentCustomFields",
+		Summary:     "Get custom document fields parsed from the document's fulltext",
+		Tags:        []string{"documents"},
+		Parameters:  []*openAPIParameter{documentIDParam},
+		Responses: errorResponses(map[string]*openAPIResponse{
+			"200": {
+				Description: "Custom fields of the document",
+				Content:     jsonContent(&jsonschema.Schema{Type: "array", Items: field}),
+			},
+		}),
+	}
+}
+
+func idwellCRMTicketOperation() *openAPIOperation {
+	return &openAPIOperation{
+		OperationID: "PutIDWELLCRMTicket",
+		Summary:     "Upsert an iDWELL CRM ticket",
+		Tags:        []string{"idwell"},
+		RequestBody: &openAPIRequestBody{
+			Required: true,
+			Content:  jsonContent(&jsonschema.Schema{Type: "object", Description: "iDWELL CRM ticket"}),
+		},
+		Responses: errorResponses(map[string]*openAPIResponse{
+			"200": {Description: "Ticket was upserted"},
+		}),
+	}
+}
@@ -0,0 +1,279 @@
+package domonda
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"strings"
+
+	"github.com/domonda/go-types/account"
+)
+
+// AccountType is the type of an account in the
+// account plan or partner export of an accounting system
+type AccountType int
+
+const (
+	// AccountTypeUnknown is used if the type can't be determined
+	AccountTypeUnknown AccountType = iota
+
+	// AccountTypeGL is a general ledger account (Sachkonto)
+	AccountTypeGL
+
+	// AccountTypeClient is a client/debtor account (Debitor, Kunde)
+	AccountTypeClient
+
+	// AccountTypeVendor is a vendor/creditor account (Kreditor, Lieferant)
+	AccountTypeVendor
+)
+
+// GLAccountNumberMaxLength is the maximum length of general ledger
+// account numbers in Austrian account plans.
+// Longer numeric account numbers are personal accounts
+// of clients (starting with 2) or vendors (starting with 3)
+// following the Austrian standard chart of accounts (Einheitskontenrahmen).
+const GLAccountNumberMaxLength = 4
+
+// ParseAccountType returns the AccountType for the value
+// of an account type column like "Sachkonto", "Kunde", or "Lieferant".
+// The abbreviations "D" and "K" follow the Debitor/Kreditor convention.
+// If value is empty, the type is derived from number,
+// see GLAccountNumberMaxLength.
+func ParseAccountType(value string, number account.Number) AccountType {
+	switch strings.ToLower(strings.TrimSpace(value)) {
+	case "":
+		num := string(number)
+		if num == "" || strings.Trim(num, "0123456789") != "" || len(num) <= GLAccountNumberMaxLength {
+			return AccountTypeGL
+		}
+		switch num[0] {
+		case '2':
+			return AccountTypeClient
+		case '3':
+			return AccountTypeVendor
+		default:
+			return AccountTypeGL
+		}
+	case "s", "g", "sachkonto", "sachkonten", "gl", "general ledger":
+		return AccountTypeGL
+	case "d", "debitor", "debitoren", "kunde", "kunden", "client", "customer", "debtor":
+		return AccountTypeClient
+	case "k", "l", "kreditor", "kreditoren", "lieferant", "lieferanten", "vendor", "supplier", "creditor":
+		return AccountTypeVendor
+	}
+	return AccountTypeUnknown
+}
+
+// accountPlanRow is a row of the account plan export
+// of an Austrian accounting system like BMD or RZL
+type accountPlanRow struct {
+	GLAccount
+
+	AccountType    string
+	AccountingArea account.NullableNumber
+}
+
+// partnerExportRow is a row of the partner export
+// of an Austrian accounting system like BMD or RZL
+// with one account number per row
+type partnerExportRow struct {
+	Partner
+
+	AccountNumber account.NullableNumber
+	AccountType   string
+}
+
+var (
+	accountPlanColumns = mergeColumns(GLAccountColumns, map[string][]string{
+		"AccountType":    {"Kontoart", "Kontotyp", "Kontenart", "Account Type"},
+		"AccountingArea": {"Buchungskreis", "Buchhaltungskreis", "Rechnungskreis", "Accounting Area"},
+	})
+
+	partnerExportColumns = mergeColumns(PartnerColumns, map[string][]string{
+		"AccountNumber": {"Konto", "Konto-Nr", "Kontonummer", "Kontonr", "Personenkonto", "Account Number"},
+		"AccountType":   {"Kontoart", "Kontotyp", "Kontenart", "Account Type"},
+	})
+)
+
+func mergeColumns(a, b map[string][]string) map[string][]string {
+	merged := maps.Clone(a)
+	maps.Copy(merged, b)
+	return merged
+}
+
+// ReadBMDAccountPlanCSV reads the general ledger accounts
+// from a BMD NTCS account plan CSV export.
+// See ReadAccountPlanCSV for details.
+// If config.Profile is nil then ColumnProfileBMD is used.
+func ReadBMDAccountPlanCSV(r io.Reader, config *CSVConfig, objects []*RealEstateObject) (accounts []*GLAccount, rowErrs []*RowError, err error) {
+	return ReadAccountPlanCSV(r, withProfile(config, ColumnProfileBMD), objects)
+}
+
+// ReadRZLAccountPlanCSV reads the general ledger accounts
+// from a RZL account plan CSV export.
+// See ReadAccountPlanCSV for details.
+// If config.Profile is nil then ColumnProfileRZL is used.
+func ReadRZLAccountPlanCSV(r io.Reader, config *CSVConfig, objects []*RealEstateObject) (accounts []*GLAccount, rowErrs []*RowError, err error) {
+	return ReadAccountPlanCSV(r, withProfile(config, ColumnProfileRZL), objects)
+}
+
+// ReadBMDPartnersCSV reads partners from a BMD NTCS
+// personal accounts (Personenkonten) CSV export.
+// See ReadPartnerAccountsCSV for details.
+// If config.Profile is nil then ColumnProfileBMD is used.
+func ReadBMDPartnersCSV(r io.Reader, config *CSVConfig) (partners []*Partner, rowErrs []*RowError, err error) {
+	return ReadPartnerAccountsCSV(r, withProfile(config, ColumnProfileBMD))
+}
+
+// ReadRZLPartnersCSV reads partners from a RZL
+// personal accounts (Personenkonten) CSV export.
+// See ReadPartnerAccountsCSV for details.
+// If config.Profile is nil then ColumnProfileRZL is used.
+func ReadRZLPartnersCSV(r io.Reader, config *CSVConfig) (partners []*Partner, rowErrs []*RowError, err error) {
+	return ReadPartnerAccountsCSV(r, withProfile(config, ColumnProfileRZL))
+}
+
+// withProfile returns a copy of config with profile
+// if config has no profile
+func withProfile(config *CSVConfig, profile *ColumnProfile) *CSVConfig {
+	c := CSVConfig{Profile: profile}
+	if config != nil {
+		c = *config
+		if c.Profile == nil {
+			c.Profile = profile
+		}
+	}
+	return &c
+}
+
+// ReadAccountPlanCSV reads the general ledger accounts from the CSV
+// account plan export of an Austrian accounting system like BMD or RZL.
+//
+// The columns are mapped like ReadGLAccountsCSV with the additional columns
+// "Kontoart" for the account type (see ParseAccountType)
+// and "Buchungskreis" or "Rechnungskreis" for the accounting area.
+// Rows of personal accounts of clients and vendors are skipped.
+//
+// In real estate accounting every object has its own accounting area.
+// If objects are passed, the ObjectNo of the accounts is set to the Number
+// of the object with the AccountingArea of the row, else the accounting area
+// is used as ObjectNo. Accounts with an ObjectNo should be posted with
+// objectSpecificAccountNos of PostGLAccounts, see HasObjectNos.
+func ReadAccountPlanCSV(r io.Reader, config *CSVConfig, objects []*RealEstateObject) (accounts []*GLAccount, rowErrs []*RowError, err error) {
+	objectNos := make(map[account.Number]account.Number)
+	for _, object := range objects {
+		if object != nil && object.AccountingArea.IsNotNull() {
+			objectNos[object.AccountingArea.Get()] = object.Number
+		}
+	}
+	rows, rowErrs, err := readCSV(r, config, accountPlanColumns, func(row *accountPlanRow) error {
+		switch ParseAccountType(row.AccountType, row.Number) {
+		case AccountTypeGL:
+		case AccountTypeClient, AccountTypeVendor:
+			return errSkipTableRow
+		default:
+			return fmt.Errorf("invalid account type %q", row.AccountType)
+		}
+		if row.AccountingArea.IsNotNull() && row.ObjectNo.IsNull() {
+			if len(objects) == 0 {
+				row.ObjectNo = row.AccountingArea
+			} else {
+				objectNo, ok := objectNos[row.AccountingArea.Get()]
+				if !ok {
+					return fmt.Errorf("no real estate object with accounting area %q", row.AccountingArea)
+				}
+				row.ObjectNo = objectNo.Nullable()
+			}
+		}
+		return row.Validate()
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	accounts = make([]*GLAccount, len(rows))
+	for i, row := range rows {
+		accounts[i] = &row.GLAccount
+	}
+	return accounts, rowErrs, nil
+}
+
+// HasObjectNos returns true if any of the accounts has an ObjectNo
+// which means that the accounts should be posted with
+// objectSpecificAccountNos of PostGLAccounts.
+func HasObjectNos(accounts []*GLAccount) bool {
+	for _, acc := range accounts {
+		if acc != nil && acc.ObjectNo.IsNotNull() {
+			return true
+		}
+	}
+	return false
+}
+
+// ReadPartnerAccountsCSV reads partners from the CSV personal accounts
+// export of an Austrian accounting system like BMD or RZL
+// where every row has one account number of a client or vendor.
+//
+// The columns are mapped like ReadPartnersCSV with the additional columns
+// "Konto-Nr" for the account number and "Kontoart" for the account type
+// (see ParseAccountType) which determines if the account number is used
+// as ClientAccountNumber or VendorAccountNumber.
+// Rows of general ledger accounts are skipped,
+// account numbers without type must follow GLAccountNumberMaxLength.
+//
+// Rows with the same partner name and VAT ID are merged
+// if their vendor and client account numbers don't conflict,
+// so that a partner who is client and vendor results in one Partner.
+// Empty fields of the first row are filled from the merged rows
+// and their bank accounts are added.
+func ReadPartnerAccountsCSV(r io.Reader, config *CSVConfig) (partners []*Partner, rowErrs []*RowError, err error) {
+	rows, rowErrs, err := readCSV(r, config, partnerExportColumns, func(row *partnerExportRow) error {
+		if row.AccountNumber.IsNotNull() {
+			switch ParseAccountType(row.AccountType, row.AccountNumber.Get()) {
+			case AccountTypeClient:
+				row.ClientAccountNumber = row.AccountNumber
+			case AccountTypeVendor:
+				row.VendorAccountNumber = row.AccountNumber
+			case AccountTypeGL:
+				if strings.TrimSpace(row.AccountType) == "" {
+					return fmt.Errorf("can't determine if account %q is a client or vendor account without account type", row.AccountNumber)
+				}
+				return errSkipTableRow
+			default:
+				return fmt.Errorf("invalid account type %q of account %q", row.AccountType, row.AccountNumber)
+			}
+		}
+		return errors.Join(withoutWarnings(row.Normalize(false))...)
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	for _, row := range rows {
+		p := &row.Partner
+		if existing := findMergeablePartner(partners, p); existing != nil {
+			// Conflicting values keep the ones of the first row
+			mergePartner(existing, p)
+			continue
+		}
+		partners = append(partners, p)
+	}
+	return partners, rowErrs, nil
+}
+
+// findMergeablePartner returns the partner with the same name and VAT ID as p
+// that has no other vendor or client account number than p
+func findMergeablePartner(partners []*Partner, p *Partner) *Partner {
+	for _, existing := range partners {
+		if !strings.EqualFold(string(existing.Name), string(p.Name)) || existing.VATIDNo != p.VATIDNo {
+			continue
+		}
+		if p.VendorAccountNumber.IsNotNull() && existing.VendorAccountNumber.IsNotNull() && existing.VendorAccountNumber != p.VendorAccountNumber {
+			continue
+		}
+		if p.ClientAccountNumber.IsNotNull() && existing.ClientAccountNumber.IsNotNull() && existing.ClientAccountNumber != p.ClientAccountNumber {
+			continue
+		}
+		return existing
+	}
+	return nil
+}
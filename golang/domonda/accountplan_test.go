@@ -0,0 +1,72 @@
+package domonda
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/domonda/go-types/account"
+)
+
+func TestParseAccountType(t *testing.T) {
+	for _, tt := range []struct {
+		value  string
+		number account.Number
+		want   AccountType
+	}{
+		{"", "4000", AccountTypeGL},
+		{"", "200001", AccountTypeClient},
+		{"", "300001", AccountTypeVendor},
+		// Longer numbers of other classes are GL accounts
+		{"", "500001", AccountTypeGL},
+		{"", "70000001", AccountTypeGL},
+		{"", "A2000", AccountTypeGL},
+		{"Sachkonto", "200001", AccountTypeGL},
+		{" Kunde ", "4000", AccountTypeClient},
+		{"K", "4000", AccountTypeVendor},
+		{"Anlage", "4000", AccountTypeUnknown},
+	} {
+		if got := ParseAccountType(tt.value, tt.number); got != tt.want {
+			t.Errorf("ParseAccountType(%q, %q) = %d, expected %d", tt.value, tt.number, got, tt.want)
+		}
+	}
+}
+
+func TestReadAccountPlanCSVWithoutType(t *testing.T) {
+	csv := "Konto;Bezeichnung\n4000;Erlöse\n500001;Materialaufwand\n200001;Kunde Muster\n"
+	accounts, rowErrs, err := ReadAccountPlanCSV(strings.NewReader(csv), nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, rowErr := range rowErrs {
+		t.Errorf("unexpected row error: %s", rowErr)
+	}
+	if len(accounts) != 2 || accounts[0].Number != "4000" || accounts[1].Number != "500001" {
+		t.Errorf("expected GL accounts 4000 and 500001, got %d accounts", len(accounts))
+	}
+}
+
+func TestReadPartnerAccountsCSVMerge(t *testing.T) {
+	csv := "Konto-Nr;Kontoart;Firmenname;Straße;Ort;Telefon;IBAN\n" +
+		"200001;Kunde;Muster GmbH;;;;\n" +
+		"300001;Lieferant;Muster GmbH;Hauptstraße 1;Wien;+43 1 234567;AT611904300234573201\n"
+	partners, rowErrs, err := ReadPartnerAccountsCSV(strings.NewReader(csv), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, rowErr := range rowErrs {
+		t.Errorf("unexpected row error: %s", rowErr)
+	}
+	if len(partners) != 1 {
+		t.Fatalf("got %d partners, expected 1 merged partner", len(partners))
+	}
+	p := partners[0]
+	if p.ClientAccountNumber != "200001" || p.VendorAccountNumber != "300001" {
+		t.Errorf("got client account %q and vendor account %q", p.ClientAccountNumber, p.VendorAccountNumber)
+	}
+	if p.Street != "Hauptstraße 1" || p.City != "Wien" || p.Phone.IsNull() {
+		t.Errorf("fields of the second row not merged: street %q, city %q, phone %q", p.Street, p.City, p.Phone)
+	}
+	if len(p.BankAccounts) != 1 || p.BankAccounts[0].IBAN != "AT611904300234573201" {
+		t.Errorf("bank account of the second row not merged: %v", p.BankAccounts)
+	}
+}
@@ -17,10 +17,10 @@ import (
 
 // BankAccount represents a checking account
 type BankAccount struct {
-	IBAN     bank.IBAN
-	BIC      bank.BIC
-	Currency money.Currency
-	Holder   notnull.TrimmedString
+	IBAN     bank.IBAN             `jsonschema:"required"`
+	BIC      bank.BIC              `jsonschema:"required"`
+	Currency money.Currency        `jsonschema:"required"`
+	Holder   notnull.TrimmedString `jsonschema:"required"`
 
 	// Optional
 	AccountNumber nullable.TrimmedString `json:",omitempty"`
@@ -61,6 +61,21 @@ func (a *BankAccount) Normalize() (err error) {
 	return err
 }
 
+// NormalizeWithBankDirectory sets a missing BIC from the bank directory dir
+// using the IBAN, then calls Normalize, and also returns an error
+// if the BIC is different from the BIC of the directory,
+// see BankDirectory.CheckBIC.
+func (a *BankAccount) NormalizeWithBankDirectory(dir *BankDirectory) (err error) {
+	if a.BIC == "" {
+		a.BIC = dir.BICForIBAN(a.IBAN)
+	}
+	err = a.Normalize()
+	if e := dir.CheckBIC(a.IBAN, a.BIC); e != nil {
+		err = errors.Join(err, e)
+	}
+	return err
+}
+
 type ImportBankAccountResult struct {
 	// ID of the bank account that was created or updated
 	ID uu.NullableID `json:",omitzero"`
@@ -68,12 +83,27 @@ type ImportBankAccountResult struct {
 	BankAccount
 
 	// State of the account after import
-	State ImportState
+	State ImportState `jsonschema:"required"`
 
 	// Error message from the import in case of State "ERROR"
 	Error string `json:",omitempty"`
 }
 
+// ImportState implements the ImportResult interface
+func (r ImportBankAccountResult) ImportState() ImportState {
+	return r.State
+}
+
+// ImportError implements the ImportResult interface
+func (r ImportBankAccountResult) ImportError() string {
+	return r.Error
+}
+
+// ImportItem implements the ImportResult interface
+func (r ImportBankAccountResult) ImportItem() string {
+	return r.IBAN.String()
+}
+
 // PostBankAccounts posts the given bankAccounts to the domonda API.
 //
 // Arguments:
@@ -0,0 +1,365 @@
+package domonda
+
+import (
+	"bufio"
+	"bytes"
+	_ "embed"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/domonda/go-types/bank"
+	"github.com/domonda/go-types/country"
+)
+
+// bank-directory.csv is generated from the official directories
+// of the Deutsche Bundesbank, the Oesterreichische Nationalbank,
+// and SIX Interbank Clearing, see internal/gen-bank-directory.
+//
+//go:generate go run ./internal/gen-bank-directory -out bank-directory.csv
+//go:embed bank-directory.csv
+var bankDirectoryCSV []byte
+
+// BankDirectoryEntry is a bank of a BankDirectory
+type BankDirectoryEntry struct {
+	// Country of the bank
+	Country country.Code
+
+	// BankCode is the national bank code used in IBANs
+	// like the German Bankleitzahl or the Swiss IID
+	BankCode string
+
+	// BIC of the bank
+	BIC bank.BIC
+
+	// Name of the bank
+	Name string
+}
+
+// bankCodeLengths are the lengths of the national bank codes
+// following the country code and check digits of IBANs
+var bankCodeLengths = map[country.Code]int{
+	country.AT: 5,
+	country.CH: 5,
+	country.DE: 8,
+	country.LI: 5,
+}
+
+// BankDirectory derives the BIC and bank name from IBANs
+// using the national bank codes of the IBANs.
+// Bank codes are supported for IBANs of Austria, Germany,
+// Switzerland, and Liechtenstein.
+//
+// A BankDirectory is safe for concurrent use.
+type BankDirectory struct {
+	mtx   sync.RWMutex
+	banks map[country.Code]map[string]*BankDirectoryEntry
+}
+
+// NewBankDirectory returns an empty BankDirectory.
+// Use the Load methods to add the official bank directories.
+func NewBankDirectory() *BankDirectory {
+	return &BankDirectory{banks: make(map[country.Code]map[string]*BankDirectoryEntry)}
+}
+
+// embeddedBanks are the parsed entries of the embedded bank-directory.csv
+var embeddedBanks = sync.OnceValue(func() []BankDirectoryEntry {
+	records, _, err := readDirectoryCSV(bankDirectoryCSV)
+	if err != nil {
+		panic(fmt.Errorf("embedded bank-directory.csv: %w", err))
+	}
+	entries := make([]BankDirectoryEntry, 0, len(records)-1)
+	for _, record := range records[1:] {
+		entries = append(entries, BankDirectoryEntry{
+			Country:  country.Code(record[0]),
+			BankCode: record[1],
+			BIC:      bank.BIC(record[2]),
+			Name:     record[3],
+		})
+	}
+	return entries
+})
+
+// DefaultBankDirectory returns a new BankDirectory with the banks
+// of Austria, Germany, Switzerland, and Liechtenstein embedded
+// from bank-directory.csv which is generated from the official
+// directories with go generate.
+// Load more recent official directories with
+// LoadBundesbank, LoadOeNB, and LoadSIX to update the banks.
+// Every call returns a new directory, so loading into it
+// adds to the embedded banks without changing
+// the directories returned to other callers.
+func DefaultBankDirectory() *BankDirectory {
+	d := NewBankDirectory()
+	for _, entry := range embeddedBanks() {
+		d.Add(&entry)
+	}
+	return d
+}
+
+// Add adds or replaces the entry for the Country and BankCode of entry
+func (d *BankDirectory) Add(entry *BankDirectoryEntry) {
+	d.mtx.Lock()
+	defer d.mtx.Unlock()
+
+	if d.banks[entry.Country] == nil {
+		d.banks[entry.Country] = make(map[string]*BankDirectoryEntry)
+	}
+	d.banks[entry.Country][entry.BankCode] = entry
+}
+
+// Len returns the number of banks in the directory
+func (d *BankDirectory) Len() int {
+	d.mtx.RLock()
+	defer d.mtx.RUnlock()
+
+	n := 0
+	for _, banks := range d.banks {
+		n += len(banks)
+	}
+	return n
+}
+
+// LookupIBAN returns the bank of the IBAN
+// or nil if the bank code of the IBAN is not in the directory.
+func (d *BankDirectory) LookupIBAN(iban bank.IBAN) *BankDirectoryEntry {
+	iban, err := iban.Normalized()
+	if err != nil {
+		return nil
+	}
+	countryCode := iban.CountryCode()
+	length, ok := bankCodeLengths[countryCode]
+	if !ok || len(iban) < 4+length {
+		return nil
+	}
+	if countryCode == country.LI {
+		// Liechtenstein uses the Swiss bank codes
+		countryCode = country.CH
+	}
+	d.mtx.RLock()
+	defer d.mtx.RUnlock()
+
+	return d.banks[countryCode][string(iban[4:4+length])]
+}
+
+// BICForIBAN returns the BIC of the bank of the IBAN
+// or an empty string if not found.
+func (d *BankDirectory) BICForIBAN(iban bank.IBAN) bank.BIC {
+	if entry := d.LookupIBAN(iban); entry != nil {
+		return entry.BIC
+	}
+	return ""
+}
+
+// CheckBIC returns an error if the bank of the IBAN
+// is in the directory with a different BIC.
+// Only the first 8 characters of the BICs are compared
+// because the branch code may differ.
+func (d *BankDirectory) CheckBIC(iban bank.IBAN, bic bank.BIC) error {
+	entry := d.LookupIBAN(iban)
+	if entry == nil || bic == "" {
+		return nil
+	}
+	if !strings.EqualFold(string(bic.TrimBranchCode()), string(entry.BIC.TrimBranchCode())) {
+		return fmt.Errorf("BIC '%s' is different from BIC '%s' of %s for IBAN '%s'", bic, entry.BIC, entry.Name, iban)
+	}
+	return nil
+}
+
+// WriteCSV writes the entries of the directory sorted by
+// Country and BankCode as CSV with the columns Country, BankCode, BIC,
+// and Name separated by semicolons in the format of the embedded
+// bank-directory.csv used by DefaultBankDirectory.
+func (d *BankDirectory) WriteCSV(w io.Writer) error {
+	d.mtx.RLock()
+	var entries []*BankDirectoryEntry
+	for _, banks := range d.banks {
+		for _, entry := range banks {
+			entries = append(entries, entry)
+		}
+	}
+	d.mtx.RUnlock()
+
+	slices.SortFunc(entries, func(a, b *BankDirectoryEntry) int {
+		if c := strings.Compare(string(a.Country), string(b.Country)); c != 0 {
+			return c
+		}
+		return strings.Compare(a.BankCode, b.BankCode)
+	})
+	writer := csv.NewWriter(w)
+	writer.Comma = ';'
+	if err := writer.Write([]string{"Country", "BankCode", "BIC", "Name"}); err != nil {
+		return err
+	}
+	for _, entry := range entries {
+		if err := writer.Write([]string{string(entry.Country), entry.BankCode, string(entry.BIC), entry.Name}); err != nil {
+			return err
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+// LoadBundesbank loads the banks from the fixed width text file
+// "Bankleitzahlendatei" published by the Deutsche Bundesbank.
+// Only banks with a BIC are added.
+func (d *BankDirectory) LoadBundesbank(r io.Reader) error {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return err
+	}
+	data, err = decodeCSV(data, "")
+	if err != nil {
+		return err
+	}
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for line := 1; scanner.Scan(); line++ {
+		// Fields by character position:
+		// 1-8 Bankleitzahl, 10-67 Bezeichnung, 108-134 Kurzbezeichnung, 140-150 BIC
+		record := []rune(scanner.Text())
+		if len(record) == 0 {
+			continue
+		}
+		if len(record) < 150 {
+			return &RowError{Line: line, Err: fmt.Errorf("expected at least 150 characters but got %d", len(record))}
+		}
+		field := func(from, to int) string {
+			return strings.TrimSpace(string(record[from-1 : to]))
+		}
+		bic := field(140, 150)
+		if bic == "" {
+			continue
+		}
+		name := field(10, 67)
+		if name == "" {
+			name = field(108, 134)
+		}
+		if err := d.add(country.DE, field(1, 8), bic, name); err != nil {
+			return &RowError{Line: line, Err: err}
+		}
+	}
+	return scanner.Err()
+}
+
+// LoadOeNB loads the banks from the CSV bank directory
+// published by the Oesterreichische Nationalbank
+// with the columns "Bankleitzahl", "SWIFT-Code", and "Bankenname".
+// Lines before the header line are ignored.
+func (d *BankDirectory) LoadOeNB(r io.Reader) error {
+	return d.loadCSV(r, country.AT,
+		[]string{"Bankleitzahl", "BLZ"},
+		[]string{"SWIFT-Code", "BIC"},
+		[]string{"Bankenname", "Name"},
+	)
+}
+
+// LoadSIX loads the banks from the CSV bank master data
+// published by SIX Interbank Clearing for Switzerland and Liechtenstein
+// with the columns "IID" or "BC-Nr", "BIC" or "SWIFT", and the bank name.
+// Lines before the header line are ignored.
+func (d *BankDirectory) LoadSIX(r io.Reader) error {
+	return d.loadCSV(r, country.CH,
+		[]string{"IID", "BC-Nr", "BC-Nummer", "Bankenclearing-Nr"},
+		[]string{"BIC", "SWIFT", "SWIFT-BIC"},
+		[]string{"Bank/Institution Name", "Bankname", "Name", "Kurzbez"},
+	)
+}
+
+func (d *BankDirectory) loadCSV(r io.Reader, countryCode country.Code, codeColumns, bicColumns, nameColumns []string) error {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return err
+	}
+	data, err = decodeCSV(data, "")
+	if err != nil {
+		return err
+	}
+	records, lines, err := readDirectoryCSV(data)
+	if err != nil {
+		return err
+	}
+	for i, header := range records {
+		codeCol, bicCol, nameCol := directoryColumn(header, codeColumns), directoryColumn(header, bicColumns), directoryColumn(header, nameColumns)
+		if codeCol == -1 || bicCol == -1 {
+			continue
+		}
+		var errs []error
+		for j, record := range records[i+1:] {
+			field := func(col int) string {
+				if col < 0 || col >= len(record) {
+					return ""
+				}
+				return strings.TrimSpace(record[col])
+			}
+			if field(codeCol) == "" || field(bicCol) == "" {
+				continue
+			}
+			if err := d.add(countryCode, field(codeCol), field(bicCol), field(nameCol)); err != nil {
+				errs = append(errs, &RowError{Line: lines[i+1+j], Err: err})
+			}
+		}
+		return errors.Join(errs...)
+	}
+	return fmt.Errorf("no header line with the columns %q and %q found", codeColumns[0], bicColumns[0])
+}
+
+func (d *BankDirectory) add(countryCode country.Code, bankCode, bic, name string) error {
+	if length := bankCodeLengths[countryCode]; len(bankCode) < length {
+		bankCode = strings.Repeat("0", length-len(bankCode)) + bankCode
+	}
+	if len(bankCode) != bankCodeLengths[countryCode] || strings.Trim(bankCode, "0123456789") != "" {
+		return fmt.Errorf("invalid bank code %q", bankCode)
+	}
+	normalized, err := bank.BIC(bic).Normalized()
+	if err != nil {
+		return fmt.Errorf("invalid BIC %q of bank code %s: %w", bic, bankCode, err)
+	}
+	d.Add(&BankDirectoryEntry{Country: countryCode, BankCode: bankCode, BIC: normalized, Name: name})
+	return nil
+}
+
+// readDirectoryCSV reads all records of CSV data
+// with the delimiter of the first line containing one
+// and returns the line numbers of the records
+// because empty lines are skipped
+func readDirectoryCSV(data []byte) (records [][]string, lines []int, err error) {
+	reader := csv.NewReader(bytes.NewReader(data))
+	reader.Comma = ';'
+	for line := range bytes.SplitSeq(data, []byte("\n")) {
+		if bytes.ContainsAny(line, ";,\t") {
+			reader.Comma = detectCSVDelimiter(line)
+			break
+		}
+	}
+	reader.FieldsPerRecord = -1
+	reader.LazyQuotes = true
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			return records, lines, nil
+		}
+		if err != nil {
+			return nil, nil, err
+		}
+		line, _ := reader.FieldPos(0)
+		records = append(records, record)
+		lines = append(lines, line)
+	}
+}
+
+// directoryColumn returns the index of the first column of header
+// matching one of the names or -1
+func directoryColumn(header, names []string) int {
+	for _, name := range names {
+		if i := slices.IndexFunc(header, func(column string) bool {
+			return normalizeColumnName(column) == normalizeColumnName(name)
+		}); i >= 0 {
+			return i
+		}
+	}
+	return -1
+}
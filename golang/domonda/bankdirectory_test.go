@@ -0,0 +1,150 @@
+package domonda
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/domonda/go-types/bank"
+	"github.com/domonda/go-types/country"
+)
+
+// bundesbankLine returns a line of the Bankleitzahlendatei
+// with the official field widths
+func bundesbankLine(blz, flag, name, shortName, bic string) string {
+	return fmt.Sprintf("%-8s%-1s%-58s%-5s%-35s%-27s%-5s%-11s%-2s%-6s%-1s%-1s%-8s",
+		blz, flag, name, "50667", "Köln", shortName, "13000", bic, "13", "000001", "U", "0", "00000000")
+}
+
+func TestBankDirectoryLoadBundesbank(t *testing.T) {
+	lines := []string{
+		bundesbankLine("37040044", "1", "Commerzbank", "Commerzbank Köln", "COBADEFFXXX"),
+		// Branches without BIC are skipped
+		bundesbankLine("37040044", "2", "Commerzbank Filiale", "Commerzbank Bonn", ""),
+		bundesbankLine("37050198", "1", "", "Sparkasse KölnBonn", "COLSDE33"),
+		"",
+	}
+	dir := NewBankDirectory()
+	if err := dir.LoadBundesbank(strings.NewReader(strings.Join(lines, "\n"))); err != nil {
+		t.Fatal(err)
+	}
+	if dir.Len() != 2 {
+		t.Fatalf("expected 2 banks but got %d", dir.Len())
+	}
+	entry := dir.LookupIBAN("DE89 3704 0044 0532 0130 00")
+	if entry == nil {
+		t.Fatal("bank of IBAN not found")
+	}
+	if entry.BankCode != "37040044" || entry.BIC != "COBADEFFXXX" || entry.Name != "Commerzbank" {
+		t.Errorf("unexpected entry %+v", entry)
+	}
+	// The short name is used without name
+	if entry := dir.banks[country.DE]["37050198"]; entry == nil || entry.Name != "Sparkasse KölnBonn" || entry.BIC != "COLSDE33XXX" {
+		t.Errorf("unexpected entry %+v", entry)
+	}
+
+	err := dir.LoadBundesbank(strings.NewReader("37040044 Commerzbank\n"))
+	if rowErr, ok := err.(*RowError); !ok || rowErr.Line != 1 {
+		t.Errorf("expected RowError of line 1 for short line but got %v", err)
+	}
+}
+
+func TestBankDirectoryLoadOeNB(t *testing.T) {
+	const data = "Bankstellenverzeichnis;Stand 01.10.2026\n" +
+		"\n" +
+		"Kennzeichen;Bankleitzahl;Bankenname;PLZ;SWIFT-Code\n" +
+		"H;12000;UniCredit Bank Austria AG;1020;BKAUATWWXXX\n" +
+		"H;190;Ohne BIC;1010;\n" +
+		"H;20111;Erste Bank der oesterreichischen Sparkassen AG;1100;GIBAATWW\n" +
+		"H;ABC;Ungültig;1010;BKAUATWW\n"
+	dir := NewBankDirectory()
+	// Line numbers count the lines before the header and empty lines
+	var rowErr *RowError
+	if err := dir.LoadOeNB(strings.NewReader(data)); !errors.As(err, &rowErr) || rowErr.Line != 7 {
+		t.Errorf("expected RowError of line 7 but got %v", err)
+	}
+	if dir.Len() != 2 {
+		t.Fatalf("expected 2 banks but got %d", dir.Len())
+	}
+	if bic := dir.BICForIBAN("AT74 1200 0516 4441 3212"); bic != "BKAUATWWXXX" {
+		t.Errorf("unexpected BIC %q", bic)
+	}
+	if entry := dir.banks[country.AT]["20111"]; entry == nil || entry.Name != "Erste Bank der oesterreichischen Sparkassen AG" {
+		t.Errorf("unexpected entry %+v", entry)
+	}
+
+	if err := NewBankDirectory().LoadOeNB(strings.NewReader("Name;PLZ\nBank;1010\n")); err == nil {
+		t.Error("expected error without header line")
+	}
+}
+
+func TestBankDirectoryLookupIBANLiechtenstein(t *testing.T) {
+	dir := NewBankDirectory()
+	err := dir.LoadSIX(strings.NewReader("IID;BIC;Bank/Institution Name\n8810;LILALI2XXXX;LGT Bank AG\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	entry := dir.LookupIBAN("LI21 0881 0000 2324 013A A")
+	if entry == nil {
+		t.Fatal("bank of Liechtenstein IBAN not found")
+	}
+	if entry.Country != country.CH || entry.BankCode != "08810" || entry.BIC != "LILALI2XXXX" {
+		t.Errorf("unexpected entry %+v", entry)
+	}
+	if entry := dir.LookupIBAN("DE89370400440532013000"); entry != nil {
+		t.Errorf("expected no entry but got %+v", entry)
+	}
+	if entry := dir.LookupIBAN("invalid"); entry != nil {
+		t.Errorf("expected no entry but got %+v", entry)
+	}
+}
+
+func TestBankDirectoryCheckBIC(t *testing.T) {
+	dir := NewBankDirectory()
+	dir.Add(&BankDirectoryEntry{Country: country.DE, BankCode: "37040044", BIC: "COBADEFFXXX", Name: "Commerzbank"})
+	const iban bank.IBAN = "DE89370400440532013000"
+	for _, tt := range []struct {
+		bic     bank.BIC
+		wantErr bool
+	}{
+		{"COBADEFFXXX", false},
+		{"COBADEFF", false},
+		// The branch code is not compared
+		{"COBADEFF370", false},
+		{"cobadeffxxx", false},
+		{"", false},
+		{"DEUTDEFFXXX", true},
+	} {
+		if err := dir.CheckBIC(iban, tt.bic); (err != nil) != tt.wantErr {
+			t.Errorf("CheckBIC(%q, %q) error = %v, expected error: %t", iban, tt.bic, err, tt.wantErr)
+		}
+	}
+	// Banks not in the directory can't be checked
+	if err := dir.CheckBIC("AT611904300234573201", "DEUTDEFFXXX"); err != nil {
+		t.Errorf("expected no error for unknown bank but got %v", err)
+	}
+}
+
+func TestDefaultBankDirectory(t *testing.T) {
+	a := DefaultBankDirectory()
+	if a.Len() == 0 {
+		t.Fatal("no embedded banks")
+	}
+	b := DefaultBankDirectory()
+	b.Add(&BankDirectoryEntry{Country: country.AT, BankCode: "99999", BIC: "TESTATWWXXX", Name: "Test"})
+	if a.Len() == b.Len() {
+		t.Error("adding to one default directory changed the other")
+	}
+}
+
+func TestBankDirectoryWriteCSV(t *testing.T) {
+	var buf bytes.Buffer
+	if err := DefaultBankDirectory().WriteCSV(&buf); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(buf.Bytes(), bankDirectoryCSV) {
+		t.Error("WriteCSV of DefaultBankDirectory differs from the embedded bank-directory.csv")
+	}
+}
@@ -0,0 +1,270 @@
+package bankstatement
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/domonda/go-types/bank"
+	"github.com/domonda/go-types/date"
+	"github.com/domonda/go-types/money"
+)
+
+// camtDocument contains the statements of CAMT.053 (BkToCstmrStmt),
+// the reports of CAMT.052 (BkToCstmrAcctRpt), and the notifications
+// of CAMT.054 (BkToCstmrDbtCdtNtfctn) which have the same structure.
+// The XML namespaces of the different versions are ignored.
+type camtDocument struct {
+	Statements    []camtStatement `xml:"BkToCstmrStmt>Stmt"`
+	Reports       []camtStatement `xml:"BkToCstmrAcctRpt>Rpt"`
+	Notifications []camtStatement `xml:"BkToCstmrDbtCdtNtfctn>Ntfctn"`
+}
+
+type camtStatement struct {
+	ID             string        `xml:"Id"`
+	SequenceNumber string        `xml:"ElctrncSeqNb"`
+	LegalSequence  string        `xml:"LglSeqNb"`
+	FromDate       string        `xml:"FrToDt>FrDtTm"`
+	ToDate         string        `xml:"FrToDt>ToDtTm"`
+	IBAN           string        `xml:"Acct>Id>IBAN"`
+	Currency       string        `xml:"Acct>Ccy"`
+	BIC            string        `xml:"Acct>Svcr>FinInstnId>BIC"`
+	BICFI          string        `xml:"Acct>Svcr>FinInstnId>BICFI"`
+	Balances       []camtBalance `xml:"Bal"`
+	Entries        []camtEntry   `xml:"Ntry"`
+}
+
+type camtAmount struct {
+	Value    string `xml:",chardata"`
+	Currency string `xml:"Ccy,attr"`
+}
+
+type camtDate struct {
+	Date     string `xml:"Dt"`
+	DateTime string `xml:"DtTm"`
+}
+
+// get returns the date or the date of the date time
+func (d camtDate) get() string {
+	if d.Date != "" {
+		return d.Date
+	}
+	return camtDateOf(d.DateTime)
+}
+
+type camtBalance struct {
+	Type          string     `xml:"Tp>CdOrPrtry>Cd"`
+	Amount        camtAmount `xml:"Amt"`
+	CreditOrDebit string     `xml:"CdtDbtInd"`
+	Date          camtDate   `xml:"Dt"`
+}
+
+// camtStatus is the entry status which is the code
+// as text up to version 7 and in the element Cd since version 8
+type camtStatus struct {
+	Text string `xml:",chardata"`
+	Code string `xml:"Cd"`
+}
+
+type camtEntry struct {
+	Amount         camtAmount `xml:"Amt"`
+	CreditOrDebit  string     `xml:"CdtDbtInd"`
+	Status         camtStatus `xml:"Sts"`
+	BookingDate    camtDate   `xml:"BookgDt"`
+	ValueDate      camtDate   `xml:"ValDt"`
+	Reference      string     `xml:"AcctSvcrRef"`
+	AdditionalInfo string     `xml:"AddtlNtryInf"`
+	Details        []camtTx   `xml:"NtryDtls>TxDtls"`
+}
+
+type camtTx struct {
+	Amount         camtAmount `xml:"Amt"`
+	TxAmount       camtAmount `xml:"AmtDtls>TxAmt>Amt"`
+	Reference      string     `xml:"Refs>AcctSvcrRef"`
+	EndToEndID     string     `xml:"Refs>EndToEndId"`
+	DebtorName     string     `xml:"RltdPties>Dbtr>Nm"`
+	DebtorPtyName  string     `xml:"RltdPties>Dbtr>Pty>Nm"`
+	DebtorIBAN     string     `xml:"RltdPties>DbtrAcct>Id>IBAN"`
+	CreditorName   string     `xml:"RltdPties>Cdtr>Nm"`
+	CreditorPty    string     `xml:"RltdPties>Cdtr>Pty>Nm"`
+	CreditorIBAN   string     `xml:"RltdPties>CdtrAcct>Id>IBAN"`
+	DebtorBIC      string     `xml:"RltdAgts>DbtrAgt>FinInstnId>BIC"`
+	DebtorBICFI    string     `xml:"RltdAgts>DbtrAgt>FinInstnId>BICFI"`
+	CreditorBIC    string     `xml:"RltdAgts>CdtrAgt>FinInstnId>BIC"`
+	CreditorBICFI  string     `xml:"RltdAgts>CdtrAgt>FinInstnId>BICFI"`
+	Unstructured   []string   `xml:"RmtInf>Ustrd"`
+	CreditorRef    string     `xml:"RmtInf>Strd>CdtrRefInf>Ref"`
+	AdditionalInfo string     `xml:"AddtlTxInf"`
+}
+
+// ParseCAMT parses the statements of CAMT.053, the account reports of CAMT.052,
+// or the debit/credit notifications of CAMT.054 XML data of any version.
+// Statements with invalid balances or entries are returned
+// without them together with the errors.
+//
+// An entry with multiple transaction details like a batch booking
+// results in one Transaction per transaction detail.
+// The partner of incoming transactions is the debtor
+// and the partner of outgoing transactions is the creditor.
+func ParseCAMT(data []byte) ([]*Statement, error) {
+	var doc camtDocument
+	if err := xml.NewDecoder(bytes.NewReader(data)).Decode(&doc); err != nil {
+		return nil, fmt.Errorf("can't parse CAMT XML: %w", err)
+	}
+	camtStatements := append(append(doc.Statements, doc.Reports...), doc.Notifications...)
+	if len(camtStatements) == 0 {
+		return nil, errors.New("no CAMT statement, report, or notification found")
+	}
+	var (
+		statements []*Statement
+		errs       []error
+	)
+	for i, s := range camtStatements {
+		statement, err := s.statement()
+		if err != nil {
+			errs = append(errs, fmt.Errorf("statement %d %q: %w", i, s.ID, err))
+		}
+		statements = append(statements, statement)
+	}
+	return statements, errors.Join(errs...)
+}
+
+func (s *camtStatement) statement() (*Statement, error) {
+	statement := &Statement{
+		ID:             strings.TrimSpace(s.ID),
+		SequenceNumber: strings.TrimSpace(s.SequenceNumber),
+		IBAN:           bank.IBAN(strings.TrimSpace(s.IBAN)),
+		BIC:            bank.NullableBIC(strings.TrimSpace(s.BIC + s.BICFI)),
+		Currency:       money.Currency(strings.TrimSpace(s.Currency)),
+		From:           date.NullableDate(camtDateOf(s.FromDate)),
+		To:             date.NullableDate(camtDateOf(s.ToDate)),
+	}
+	if statement.SequenceNumber == "" {
+		statement.SequenceNumber = strings.TrimSpace(s.LegalSequence)
+	}
+	var errs []error
+	for _, b := range s.Balances {
+		amount, err := b.Amount.parse(b.CreditOrDebit, false)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("balance %s: %w", b.Type, err))
+			continue
+		}
+		if statement.Currency == "" {
+			statement.Currency = money.Currency(b.Amount.Currency)
+		}
+		balance := &Balance{Amount: amount, Date: date.Date(b.Date.get())}
+		switch b.Type {
+		case "OPBD", "PRCD":
+			if statement.OpeningBalance == nil || b.Type == "OPBD" {
+				statement.OpeningBalance = balance
+			}
+		case "CLBD":
+			statement.ClosingBalance = balance
+		}
+	}
+	for i, e := range s.Entries {
+		transactions, err := e.transactions(statement.IBAN)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("entry %d: %w", i, err))
+			continue
+		}
+		statement.Transactions = append(statement.Transactions, transactions...)
+	}
+	return statement, errors.Join(errs...)
+}
+
+func (e *camtEntry) transactions(iban bank.IBAN) ([]*Transaction, error) {
+	amount, err := e.Amount.parse("CRDT", true)
+	if err != nil {
+		return nil, err
+	}
+	// CdtDbtInd is the booked direction, also of reversals
+	typ := TransactionTypeOutgoing
+	if e.CreditOrDebit == "CRDT" {
+		typ = TransactionTypeIncoming
+	}
+	status := strings.TrimSpace(e.Status.Code)
+	if status == "" {
+		status = strings.TrimSpace(e.Status.Text)
+	}
+	entry := Transaction{
+		ID:              strings.TrimSpace(e.Reference),
+		BankAccountIBAN: iban,
+		Type:            typ,
+		Amount:          amount,
+		Currency:        money.Currency(e.Amount.Currency),
+		BookingDate:     date.Date(e.BookingDate.get()),
+		ValueDate:       date.NullableDate(e.ValueDate.get()),
+		Pending:         status != "" && status != "BOOK",
+		Title:           strings.TrimSpace(e.AdditionalInfo),
+	}
+	if len(e.Details) == 0 {
+		return []*Transaction{&entry}, nil
+	}
+	transactions := make([]*Transaction, len(e.Details))
+	for i, d := range e.Details {
+		t := entry
+		if len(e.Details) > 1 {
+			// Amt is in the currency of the account,
+			// AmtDtls>TxAmt may be in the currency of the payment
+			txAmount := d.Amount
+			if txAmount.Value == "" {
+				txAmount = d.TxAmount
+			}
+			if txAmount.Value != "" {
+				if t.Amount, err = txAmount.parse("CRDT", true); err != nil {
+					return nil, fmt.Errorf("transaction details %d: %w", i, err)
+				}
+				t.Currency = money.Currency(txAmount.Currency)
+			}
+		}
+		if ref := strings.TrimSpace(d.Reference); ref != "" {
+			t.ID = ref
+		}
+		if endToEndID := strings.TrimSpace(d.EndToEndID); endToEndID != "NOTPROVIDED" {
+			t.EndToEndID = endToEndID
+		}
+		if typ == TransactionTypeIncoming {
+			t.PartnerName = strings.TrimSpace(d.DebtorName + d.DebtorPtyName)
+			t.PartnerIBAN = bank.NullableIBAN(strings.TrimSpace(d.DebtorIBAN))
+			t.PartnerBIC = bank.NullableBIC(strings.TrimSpace(d.DebtorBIC + d.DebtorBICFI))
+		} else {
+			t.PartnerName = strings.TrimSpace(d.CreditorName + d.CreditorPty)
+			t.PartnerIBAN = bank.NullableIBAN(strings.TrimSpace(d.CreditorIBAN))
+			t.PartnerBIC = bank.NullableBIC(strings.TrimSpace(d.CreditorBIC + d.CreditorBICFI))
+		}
+		t.Purpose = strings.TrimSpace(strings.Join(d.Unstructured, ""))
+		if t.Purpose == "" {
+			t.Purpose = strings.TrimSpace(d.AdditionalInfo)
+		}
+		t.Reference = strings.TrimSpace(d.CreditorRef)
+		transactions[i] = &t
+	}
+	return transactions, nil
+}
+
+// parse returns the amount, negative if creditOrDebit is "DBIT"
+// unless abs is true
+func (a camtAmount) parse(creditOrDebit string, abs bool) (money.Amount, error) {
+	f, err := strconv.ParseFloat(strings.TrimSpace(a.Value), 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid amount %q", a.Value)
+	}
+	amount := money.Amount(f)
+	if !abs && creditOrDebit == "DBIT" {
+		amount = -amount
+	}
+	return amount, nil
+}
+
+// camtDateOf returns the date part of an ISO 8601 date time
+func camtDateOf(dateTime string) string {
+	dateTime = strings.TrimSpace(dateTime)
+	if len(dateTime) > 10 {
+		return dateTime[:10]
+	}
+	return dateTime
+}
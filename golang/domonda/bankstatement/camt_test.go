@@ -0,0 +1,160 @@
+package bankstatement
+
+import (
+	"bytes"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/domonda/go-types/money"
+)
+
+func TestParseCAMT(t *testing.T) {
+	data, err := os.ReadFile("testdata/camt053.xml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	statements, err := Parse(data)
+	if err != nil {
+		t.Fatalf("Parse: %s", err)
+	}
+	if len(statements) != 1 {
+		t.Fatalf("got %d statements, expected 1", len(statements))
+	}
+	s := statements[0]
+	if err := s.Validate(); err != nil {
+		t.Errorf("Validate: %s", err)
+	}
+	if s.ID != "STMT-2024-02-29" || s.SequenceNumber != "59" {
+		t.Errorf("got ID %q and sequence number %q", s.ID, s.SequenceNumber)
+	}
+	if s.IBAN != "DE89370400440532013000" || s.BIC != "COBADEFFXXX" || s.Currency != money.EUR {
+		t.Errorf("got IBAN %q, BIC %q, currency %q", s.IBAN, s.BIC, s.Currency)
+	}
+	if s.From != "2024-02-29" || s.To != "2024-02-29" {
+		t.Errorf("got period %s to %s", s.From, s.To)
+	}
+	if s.OpeningBalance == nil || s.OpeningBalance.Amount != -20 || s.OpeningBalance.Date != "2024-02-28" {
+		t.Errorf("got opening balance %+v, expected -20.00 at 2024-02-28", s.OpeningBalance)
+	}
+	if s.ClosingBalance == nil || s.ClosingBalance.Amount != 1180 {
+		t.Errorf("got closing balance %+v, expected 1180.00", s.ClosingBalance)
+	}
+
+	expected := []Transaction{
+		{
+			ID:              "BATCH-1-1",
+			BankAccountIBAN: "DE89370400440532013000",
+			Type:            TransactionTypeIncoming,
+			Amount:          1000,
+			Currency:        money.EUR,
+			BookingDate:     "2024-02-29",
+			ValueDate:       "2024-02-29",
+			PartnerName:     "Muster AG",
+			PartnerIBAN:     "CH9300762011623852957",
+			PartnerBIC:      "UBSWCHZH80A",
+			Title:           "SAMMELGUTSCHRIFT",
+			Reference:       "RF18539007547034",
+			EndToEndID:      "E2E-1",
+		},
+		{
+			ID:              "BATCH-1-2",
+			BankAccountIBAN: "DE89370400440532013000",
+			Type:            TransactionTypeIncoming,
+			Amount:          200,
+			Currency:        money.EUR,
+			BookingDate:     "2024-02-29",
+			ValueDate:       "2024-02-29",
+			PartnerName:     "Erika Mustermann",
+			Title:           "SAMMELGUTSCHRIFT",
+			Purpose:         "Rechnung 2024-17",
+		},
+		{
+			ID:              "PENDING-1",
+			BankAccountIBAN: "DE89370400440532013000",
+			Type:            TransactionTypeOutgoing,
+			Amount:          99,
+			Currency:        money.EUR,
+			BookingDate:     "2024-02-29",
+			Pending:         true,
+		},
+	}
+	if len(s.Transactions) != len(expected) {
+		t.Fatalf("got %d transactions, expected %d", len(s.Transactions), len(expected))
+	}
+	for i, tx := range s.Transactions {
+		if *tx != expected[i] {
+			t.Errorf("transaction %d:\ngot      %+v\nexpected %+v", i, *tx, expected[i])
+		}
+	}
+}
+
+func TestParseCAMTReversal(t *testing.T) {
+	data, err := os.ReadFile("testdata/camt053-reversal.xml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	statements, err := ParseCAMT(data)
+	if err != nil {
+		t.Fatalf("ParseCAMT: %s", err)
+	}
+	if len(statements) != 1 {
+		t.Fatalf("got %d statements, expected 1", len(statements))
+	}
+	s := statements[0]
+	// OPBD 100.00 - DBIT 50.00 + reversal CRDT 50.00 = CLBD 100.00
+	if err := s.Validate(); err != nil {
+		t.Errorf("Validate: %s", err)
+	}
+	if len(s.Transactions) != 2 {
+		t.Fatalf("got %d transactions, expected 2", len(s.Transactions))
+	}
+	debit, reversal := s.Transactions[0], s.Transactions[1]
+	if debit.Type != TransactionTypeOutgoing || debit.SignedAmount() != -50 {
+		t.Errorf("got debit %s %s, expected OUTGOING -50.00", debit.Type, debit.SignedAmount())
+	}
+	if debit.PartnerName != "ACME GmbH" || debit.PartnerIBAN != "AT611904300234573201" || debit.Purpose != "RE 4711" || debit.EndToEndID != "" {
+		t.Errorf("got debit partner %q, IBAN %q, purpose %q, end to end ID %q", debit.PartnerName, debit.PartnerIBAN, debit.Purpose, debit.EndToEndID)
+	}
+	if reversal.Type != TransactionTypeIncoming || reversal.SignedAmount() != 50 {
+		t.Errorf("got reversal %s %s, expected INCOMING 50.00", reversal.Type, reversal.SignedAmount())
+	}
+	if reversal.ID != "REF-2" || reversal.Title != "RUECKUEBERWEISUNG" {
+		t.Errorf("got reversal ID %q and title %q", reversal.ID, reversal.Title)
+	}
+}
+
+func TestParseCAMTInvalid(t *testing.T) {
+	for name, data := range map[string]string{
+		"no XML":       "not XML",
+		"no statement": `<Document><BkToCstmrStmt></BkToCstmrStmt></Document>`,
+	} {
+		t.Run(name, func(t *testing.T) {
+			if _, err := ParseCAMT([]byte(data)); err == nil {
+				t.Error("expected error")
+			}
+		})
+	}
+}
+
+func TestParseCAMTTxAmountFallback(t *testing.T) {
+	data, err := os.ReadFile("testdata/camt053.xml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Without Amt the amount of AmtDtls>TxAmt is used
+	data = bytes.Replace(data, []byte(`<Amt Ccy="EUR">1000.00</Amt>`+"\n"), nil, 1)
+	statements, err := ParseCAMT(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tx := statements[0].Transactions[0]
+	if tx.Amount != 1085.50 || tx.Currency != "USD" {
+		t.Errorf("got amount %s %s, expected 1085.50 USD", tx.Amount, tx.Currency)
+	}
+	// The foreign currency transaction doesn't sum up to the balances
+	err = statements[0].Validate()
+	if err == nil || !strings.Contains(err.Error(), `transaction 0: currency "USD" is different from statement currency "EUR"`) {
+		t.Errorf("expected currency error but got %v", err)
+	}
+}
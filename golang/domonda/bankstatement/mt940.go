@@ -0,0 +1,299 @@
+package bankstatement
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/domonda/go-types/bank"
+	"github.com/domonda/go-types/country"
+	"github.com/domonda/go-types/date"
+	"github.com/domonda/go-types/money"
+)
+
+var (
+	mt940Tag         = regexp.MustCompile(`^:(\d{2}[A-Z]?):`)
+	mt940Transaction = regexp.MustCompile(`^(\d{6})(\d{4})?(R?[CD])([A-Z])?(\d+,\d*)([A-Z][A-Z0-9]{3})(.*?)(?://(.*))?$`)
+	mt940Balance     = regexp.MustCompile(`^([CD])(\d{6})([A-Z]{3})(\d+,\d*)$`)
+	mt940SEPATag     = regexp.MustCompile(`(EREF|KREF|MREF|CRED|DEBT|SVWZ|ABWA|ABWE|IBAN|BIC)\+`)
+)
+
+type mt940Field struct {
+	line  int
+	tag   string
+	value string
+}
+
+// ParseMT940 parses SWIFT MT940 statements with the structured
+// field 86 used by German and Austrian banks.
+//
+// The IBAN of the statement is derived from field 25 which
+// contains either the IBAN or the bank code and account number
+// of German or Austrian accounts separated by a slash.
+// The purpose of field 86 is taken from the SEPA tag "SVWZ+"
+// and the end to end ID from "EREF+" if present.
+// Data that is not valid UTF-8 is decoded as Windows-1252.
+// Statements with invalid fields are returned without them
+// together with the errors.
+func ParseMT940(data []byte) ([]*Statement, error) {
+	data, err := decodeText(data)
+	if err != nil {
+		return nil, err
+	}
+	var (
+		statements []*Statement
+		fields     []mt940Field
+		errs       []error
+	)
+	flush := func() {
+		if len(fields) == 0 {
+			return
+		}
+		statement, err := parseMT940Statement(fields)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("statement %d %q: %w", len(statements), statement.ID, err))
+		}
+		statements = append(statements, statement)
+		fields = nil
+	}
+	for i, line := range strings.Split(strings.ReplaceAll(string(data), "\r\n", "\n"), "\n") {
+		line = strings.TrimRight(line, " \r")
+		switch {
+		case line == "" || strings.HasPrefix(line, "{"):
+			// Empty lines and SWIFT header blocks
+		case line == "-" || line == "-}":
+			flush()
+		case mt940Tag.MatchString(line):
+			tag := mt940Tag.FindStringSubmatch(line)[1]
+			if tag == "20" {
+				flush()
+			}
+			fields = append(fields, mt940Field{line: i + 1, tag: tag, value: line[len(tag)+2:]})
+		case len(fields) > 0:
+			// Continuation line of the previous field
+			fields[len(fields)-1].value += "\n" + line
+		}
+	}
+	flush()
+	if len(statements) == 0 {
+		return nil, errors.Join(append(errs, errors.New("no MT940 statement found"))...)
+	}
+	return statements, errors.Join(errs...)
+}
+
+func parseMT940Statement(fields []mt940Field) (*Statement, error) {
+	var (
+		statement = new(Statement)
+		last      *Transaction
+		errs      []error
+	)
+	for _, field := range fields {
+		var err error
+		switch field.tag {
+		case "20":
+			statement.ID = strings.TrimSpace(field.value)
+		case "25":
+			statement.IBAN, err = mt940IBAN(field.value)
+		case "28C":
+			statement.SequenceNumber = strings.TrimSpace(field.value)
+		case "60F", "60M":
+			statement.OpeningBalance, err = statement.parseMT940Balance(field.value)
+			if err == nil && statement.From.IsNull() && field.tag == "60F" {
+				statement.From = statement.OpeningBalance.Date.Nullable()
+			}
+		case "62F", "62M":
+			statement.ClosingBalance, err = statement.parseMT940Balance(field.value)
+			if err == nil {
+				statement.To = statement.ClosingBalance.Date.Nullable()
+			}
+		case "61":
+			last, err = parseMT940Transaction(field.value)
+			if err == nil {
+				last.BankAccountIBAN = statement.IBAN
+				last.Currency = statement.Currency
+				statement.Transactions = append(statement.Transactions, last)
+			}
+		case "86":
+			if last != nil {
+				last.parseMT940Details(field.value)
+			}
+		}
+		if err != nil {
+			errs = append(errs, fmt.Errorf("line %d field %s: %w", field.line, field.tag, err))
+		}
+	}
+	return statement, errors.Join(errs...)
+}
+
+// mt940IBAN returns the IBAN of the account identification of field 25
+func mt940IBAN(account string) (bank.IBAN, error) {
+	account = strings.TrimSpace(account)
+	if iban, err := bank.NormalizeIBAN(account); err == nil {
+		return iban, nil
+	}
+	bankCode, accountNumber, ok := strings.Cut(account, "/")
+	// The account number may be followed by the currency
+	accountNumber = strings.TrimRight(strings.TrimSpace(accountNumber), "ABCDEFGHIJKLMNOPQRSTUVWXYZ")
+	if !ok || strings.Trim(bankCode+accountNumber, "0123456789") != "" {
+		return "", fmt.Errorf("can't derive IBAN from account %q", account)
+	}
+	switch {
+	case len(bankCode) == 8 && len(accountNumber) <= 10:
+		return ibanOf(country.DE, bankCode+fmt.Sprintf("%010s", accountNumber))
+	case len(bankCode) == 5 && len(accountNumber) <= 11:
+		return ibanOf(country.AT, bankCode+fmt.Sprintf("%011s", accountNumber))
+	}
+	return "", fmt.Errorf("can't derive IBAN from account %q", account)
+}
+
+// ibanOf returns the IBAN of a numeric BBAN with calculated check digits
+func ibanOf(countryCode country.Code, bban string) (bank.IBAN, error) {
+	remainder := 0
+	for _, r := range bban + string(countryCode) + "00" {
+		if r >= 'A' && r <= 'Z' {
+			remainder = (remainder*100 + int(r-'A') + 10) % 97
+		} else {
+			remainder = (remainder*10 + int(r-'0')) % 97
+		}
+	}
+	return bank.NormalizeIBAN(fmt.Sprintf("%s%02d%s", countryCode, 98-remainder, bban))
+}
+
+// parseMT940Balance parses a balance like "C240131EUR1234,56"
+// and sets the statement currency if not set
+func (s *Statement) parseMT940Balance(value string) (*Balance, error) {
+	m := mt940Balance.FindStringSubmatch(strings.TrimSpace(value))
+	if m == nil {
+		return nil, fmt.Errorf("invalid balance %q", value)
+	}
+	d, err := date.Parse("060102", m[2])
+	if err != nil {
+		return nil, fmt.Errorf("invalid balance date %q", m[2])
+	}
+	amount, err := parseMT940Amount(m[4])
+	if err != nil {
+		return nil, err
+	}
+	if s.Currency == "" {
+		s.Currency = money.Currency(m[3])
+	}
+	return &Balance{Amount: amount.WithNegSign(m[1] == "D"), Date: d}, nil
+}
+
+// parseMT940Transaction parses the statement line of field 61
+// like "2401310131D123,45NTRFNONREF//BANKREF"
+func parseMT940Transaction(value string) (*Transaction, error) {
+	line, _, _ := strings.Cut(value, "\n")
+	m := mt940Transaction.FindStringSubmatch(strings.TrimSpace(line))
+	if m == nil {
+		return nil, fmt.Errorf("invalid statement line %q", line)
+	}
+	valueDate, err := date.Parse("060102", m[1])
+	if err != nil {
+		return nil, fmt.Errorf("invalid value date %q", m[1])
+	}
+	amount, err := parseMT940Amount(m[5])
+	if err != nil {
+		return nil, err
+	}
+	t := &Transaction{
+		ID:          strings.TrimSpace(m[8]),
+		Type:        TransactionTypeOutgoing,
+		Amount:      amount,
+		BookingDate: valueDate,
+		ValueDate:   valueDate.Nullable(),
+	}
+	// "RD" is the reversal of a debit which is booked as credit
+	if m[3] == "C" || m[3] == "RD" {
+		t.Type = TransactionTypeIncoming
+	}
+	if m[2] != "" {
+		// The entry date has no year, take the year
+		// of the value date closest to the entry date
+		year := valueDate.Year()
+		bookingDate, err := date.Parse("20060102", fmt.Sprintf("%04d%s", year, m[2]))
+		if err != nil {
+			return nil, fmt.Errorf("invalid entry date %q", m[2])
+		}
+		switch diff := bookingDate.Sub(valueDate).Hours() / 24; {
+		case diff > 180:
+			bookingDate, _ = date.Parse("20060102", fmt.Sprintf("%04d%s", year-1, m[2]))
+		case diff < -180:
+			bookingDate, _ = date.Parse("20060102", fmt.Sprintf("%04d%s", year+1, m[2]))
+		}
+		t.BookingDate = bookingDate
+	}
+	if t.ID == "" {
+		if customerRef := strings.TrimSpace(m[7]); customerRef != "NONREF" {
+			t.ID = customerRef
+		}
+	}
+	return t, nil
+}
+
+// parseMT940Details sets the fields of t from the information
+// to account owner of field 86 which is either structured with
+// subfields like "?00" or unstructured text used as purpose
+func (t *Transaction) parseMT940Details(value string) {
+	value = strings.ReplaceAll(value, "\n", "")
+	// Structured field 86 begins with a business transaction code
+	// followed by the separator of the subfields, usually '?'
+	if len(value) < 4 || strings.Trim(value[:3], "0123456789") != "" {
+		t.Purpose = strings.TrimSpace(value)
+		return
+	}
+	separator := value[3:4]
+	var purpose, name strings.Builder
+	for _, subfield := range strings.Split(value[4:], separator) {
+		if len(subfield) < 2 {
+			continue
+		}
+		code, text := subfield[:2], subfield[2:]
+		switch {
+		case code == "00":
+			t.Title = strings.TrimSpace(text)
+		case code >= "20" && code <= "29", code >= "60" && code <= "63":
+			purpose.WriteString(text)
+		case code == "30":
+			if bic := bank.BIC(strings.TrimSpace(text)); bic.Valid() {
+				t.PartnerBIC = bic.Nullable()
+			}
+		case code == "31":
+			if iban, err := bank.NormalizeIBAN(text); err == nil {
+				t.PartnerIBAN = iban.Nullable()
+			}
+		case code == "32", code == "33":
+			name.WriteString(text)
+		}
+	}
+	t.PartnerName = strings.TrimSpace(name.String())
+	t.Purpose = strings.TrimSpace(purpose.String())
+	tags := mt940SEPATag.FindAllStringSubmatchIndex(t.Purpose, -1)
+	if len(tags) == 0 {
+		return
+	}
+	sepa := make(map[string]string, len(tags))
+	for i, tag := range tags {
+		end := len(t.Purpose)
+		if i+1 < len(tags) {
+			end = tags[i+1][0]
+		}
+		sepa[t.Purpose[tag[2]:tag[3]]] = strings.TrimSpace(t.Purpose[tag[1]:end])
+	}
+	if eref := sepa["EREF"]; eref != "NOTPROVIDED" {
+		t.EndToEndID = eref
+	}
+	if svwz, ok := sepa["SVWZ"]; ok {
+		t.Purpose = svwz
+	}
+}
+
+func parseMT940Amount(s string) (money.Amount, error) {
+	f, err := strconv.ParseFloat(strings.Replace(s, ",", ".", 1), 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid amount %q", s)
+	}
+	return money.Amount(f), nil
+}
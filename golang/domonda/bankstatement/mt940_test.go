@@ -0,0 +1,106 @@
+package bankstatement
+
+import (
+	"os"
+	"testing"
+
+	"github.com/domonda/go-types/bank"
+	"github.com/domonda/go-types/money"
+)
+
+func TestParseMT940(t *testing.T) {
+	data, err := os.ReadFile("testdata/mt940.sta")
+	if err != nil {
+		t.Fatal(err)
+	}
+	statements, err := Parse(data)
+	if err != nil {
+		t.Fatalf("Parse: %s", err)
+	}
+	if len(statements) != 1 {
+		t.Fatalf("got %d statements, expected 1", len(statements))
+	}
+	s := statements[0]
+	// 100.00 - 50.00 + reversal 50.00 + 119.00 = 219.00
+	if err := s.Validate(); err != nil {
+		t.Errorf("Validate: %s", err)
+	}
+	if s.ID != "STARTUMS" || s.SequenceNumber != "00001/001" {
+		t.Errorf("got ID %q and sequence number %q", s.ID, s.SequenceNumber)
+	}
+	// Derived from the bank code and account number of field 25
+	if s.IBAN != "DE89370400440532013000" || s.Currency != money.EUR {
+		t.Errorf("got IBAN %q and currency %q", s.IBAN, s.Currency)
+	}
+	if s.From != "2024-01-30" || s.To != "2024-02-01" {
+		t.Errorf("got period %s to %s", s.From, s.To)
+	}
+
+	expected := []Transaction{
+		{
+			ID:              "BANKREF1",
+			BankAccountIBAN: "DE89370400440532013000",
+			Type:            TransactionTypeOutgoing,
+			Amount:          50,
+			Currency:        money.EUR,
+			BookingDate:     "2024-01-31",
+			ValueDate:       "2024-01-31",
+			PartnerName:     "ACME GmbH",
+			PartnerIBAN:     "AT611904300234573201",
+			PartnerBIC:      "COBADEFFXXX",
+			Title:           "SEPA-UEBERWEISUNG",
+			Purpose:         "RE 4711Danke",
+			EndToEndID:      "E2E-4711",
+		},
+		{
+			ID:              "BANKREF2",
+			BankAccountIBAN: "DE89370400440532013000",
+			Type:            TransactionTypeIncoming,
+			Amount:          50,
+			Currency:        money.EUR,
+			BookingDate:     "2024-01-31",
+			ValueDate:       "2024-01-31",
+			PartnerName:     "ACME GmbH",
+			Title:           "RUECKUEBERWEISUNG",
+			Purpose:         "RE 4711",
+		},
+		{
+			ID:              "KUNDENREF",
+			BankAccountIBAN: "DE89370400440532013000",
+			Type:            TransactionTypeIncoming,
+			Amount:          119,
+			Currency:        money.EUR,
+			BookingDate:     "2024-02-01",
+			ValueDate:       "2024-01-31",
+			Purpose:         "Gutschrift Mietzahlung Januar",
+		},
+	}
+	if len(s.Transactions) != len(expected) {
+		t.Fatalf("got %d transactions, expected %d", len(s.Transactions), len(expected))
+	}
+	for i, tx := range s.Transactions {
+		if *tx != expected[i] {
+			t.Errorf("transaction %d:\ngot      %+v\nexpected %+v", i, *tx, expected[i])
+		}
+	}
+}
+
+func TestMT940IBAN(t *testing.T) {
+	for _, tt := range []struct {
+		account string
+		iban    string
+	}{
+		{"DE89370400440532013000", "DE89370400440532013000"},
+		{"37040044/532013000", "DE89370400440532013000"},
+		{"37040044/0532013000EUR", "DE89370400440532013000"},
+		{"19043/00234573201", "AT611904300234573201"},
+	} {
+		iban, err := mt940IBAN(tt.account)
+		if err != nil || iban != bank.IBAN(tt.iban) {
+			t.Errorf("mt940IBAN(%q) = %q, %v, expected %s", tt.account, iban, err, tt.iban)
+		}
+	}
+	if _, err := mt940IBAN("12/34"); err == nil {
+		t.Error("expected error for unknown account format")
+	}
+}
@@ -0,0 +1,242 @@
+// Package bankstatement parses bank statements in the formats
+// CAMT.053, CAMT.052, CAMT.054, and MT940 into typed transactions
+// to check statements and match them to invoices before uploading them
+// as documents of type BANK_STATEMENT.
+package bankstatement
+
+//go:generate go tool go-enum $GOFILE
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"unicode/utf8"
+
+	"github.com/domonda/go-types/bank"
+	"github.com/domonda/go-types/charset"
+	"github.com/domonda/go-types/date"
+	"github.com/domonda/go-types/money"
+	"github.com/domonda/go-types/notnull"
+
+	"github.com/domonda/api/golang/domonda"
+)
+
+// TransactionType is the direction of a transaction
+// like the BankTransactionType of the GraphQL API.
+type TransactionType string //#enum
+
+const (
+	// TransactionTypeIncoming is money credited to the account
+	TransactionTypeIncoming TransactionType = "INCOMING"
+
+	// TransactionTypeOutgoing is money debited from the account
+	TransactionTypeOutgoing TransactionType = "OUTGOING"
+)
+
+// Valid indicates if t is any of the valid values for TransactionType
+func (t TransactionType) Valid() bool {
+	switch t {
+	case
+		TransactionTypeIncoming,
+		TransactionTypeOutgoing:
+		return true
+	}
+	return false
+}
+
+// Validate returns an error if t is none of the valid values for TransactionType
+func (t TransactionType) Validate() error {
+	if !t.Valid() {
+		return fmt.Errorf("invalid value %#v for type bankstatement.TransactionType", t)
+	}
+	return nil
+}
+
+// Enums returns all valid values for TransactionType
+func (TransactionType) Enums() []TransactionType {
+	return []TransactionType{
+		TransactionTypeIncoming,
+		TransactionTypeOutgoing,
+	}
+}
+
+// EnumStrings returns all valid values for TransactionType as strings
+func (TransactionType) EnumStrings() []string {
+	return []string{
+		"INCOMING",
+		"OUTGOING",
+	}
+}
+
+// String implements the fmt.Stringer interface for TransactionType
+func (t TransactionType) String() string {
+	return string(t)
+}
+
+// Statement is a bank statement of one bank account
+type Statement struct {
+	// ID of the statement like the CAMT statement Id
+	// or the MT940 transaction reference number (field 20)
+	ID string
+
+	// SequenceNumber of the statement like the CAMT electronic
+	// sequence number or the MT940 statement number (field 28C)
+	SequenceNumber string
+
+	// IBAN of the bank account
+	IBAN bank.IBAN
+
+	// BIC of the bank if available
+	BIC bank.NullableBIC
+
+	// Currency of the bank account
+	Currency money.Currency
+
+	// From is the first date of the statement period if available
+	From date.NullableDate
+
+	// To is the last date of the statement period if available
+	To date.NullableDate
+
+	// OpeningBalance of the statement if available
+	OpeningBalance *Balance
+
+	// ClosingBalance of the statement if available
+	ClosingBalance *Balance
+
+	// Transactions of the statement in the order of the statement
+	Transactions []*Transaction
+}
+
+// Balance is a booked balance of a bank account
+type Balance struct {
+	// Amount of the balance, negative for a debit balance
+	Amount money.Amount
+
+	// Date of the balance
+	Date date.Date
+}
+
+// Transaction is a transaction of a bank statement
+// with the fields of the BankTransaction type of the GraphQL API.
+type Transaction struct {
+	// ID is the reference of the bank for the transaction if available
+	ID string
+
+	// BankAccountIBAN is the IBAN of the statement's bank account
+	BankAccountIBAN bank.IBAN
+
+	// Type is INCOMING or OUTGOING
+	Type TransactionType
+
+	// Amount of the transaction, always positive
+	Amount money.Amount
+
+	// Currency of Amount
+	Currency money.Currency
+
+	// BookingDate of the transaction
+	BookingDate date.Date
+
+	// ValueDate of the transaction if available
+	ValueDate date.NullableDate
+
+	// Pending is true for transactions that are not booked yet
+	Pending bool
+
+	// PartnerName is the name of the debtor of incoming
+	// or the creditor of outgoing transactions
+	PartnerName string
+
+	// PartnerIBAN is the IBAN of the partner if available
+	PartnerIBAN bank.NullableIBAN
+
+	// PartnerBIC is the BIC of the partner's bank if available
+	PartnerBIC bank.NullableBIC
+
+	// Title is the posting text of the bank like "GUTSCHRIFT"
+	Title string
+
+	// Purpose is the unstructured remittance information
+	Purpose string
+
+	// Reference is the structured creditor reference like "RF18539007547034"
+	Reference string
+
+	// EndToEndID is the end to end identification of the payment
+	// or empty if not provided ("NOTPROVIDED")
+	EndToEndID string
+}
+
+// SignedAmount returns Amount with negative sign for outgoing transactions
+func (t *Transaction) SignedAmount() money.Amount {
+	return t.Amount.WithNegSign(t.Type == TransactionTypeOutgoing)
+}
+
+// BankAccount returns the bank account of the statement
+// as domonda.BankAccount with holder.
+func (s *Statement) BankAccount(holder string) *domonda.BankAccount {
+	return &domonda.BankAccount{
+		IBAN:     s.IBAN,
+		BIC:      bank.BIC(s.BIC),
+		Currency: s.Currency,
+		Holder:   notnull.TrimmedString(holder),
+	}
+}
+
+// Validate checks the IBAN and Currency of the statement and its transactions,
+// that all transactions have the Currency of the statement,
+// and that the closing balance is the opening balance plus the
+// booked transactions if the statement has both balances.
+func (s *Statement) Validate() error {
+	var errs []error
+	if err := s.IBAN.Validate(); err != nil {
+		errs = append(errs, fmt.Errorf("invalid statement IBAN %q: %w", s.IBAN, err))
+	}
+	if err := s.Currency.Validate(); err != nil {
+		errs = append(errs, fmt.Errorf("invalid statement currency %q: %w", s.Currency, err))
+	}
+	var sum money.Amount
+	for i, t := range s.Transactions {
+		if err := t.Type.Validate(); err != nil {
+			errs = append(errs, fmt.Errorf("transaction %d: %w", i, err))
+		}
+		if !t.Amount.ValidAndPositive() {
+			errs = append(errs, fmt.Errorf("transaction %d: invalid amount %s", i, t.Amount))
+		}
+		if t.Currency != s.Currency {
+			errs = append(errs, fmt.Errorf("transaction %d: currency %q is different from statement currency %q", i, t.Currency, s.Currency))
+		}
+		if err := t.BookingDate.Validate(); err != nil {
+			errs = append(errs, fmt.Errorf("transaction %d: invalid booking date: %w", i, err))
+		}
+		if !t.Pending {
+			sum += t.SignedAmount()
+		}
+	}
+	if s.OpeningBalance != nil && s.ClosingBalance != nil {
+		expected := (s.OpeningBalance.Amount + sum).RoundToCents()
+		if !expected.WithinOneCent(s.ClosingBalance.Amount) {
+			errs = append(errs, fmt.Errorf("closing balance %s is different from opening balance %s plus transactions %s", s.ClosingBalance.Amount, s.OpeningBalance.Amount, sum.RoundToCents()))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Parse parses CAMT XML or MT940 data detected from the first characters.
+func Parse(data []byte) ([]*Statement, error) {
+	trimmed := bytes.TrimSpace(bytes.TrimPrefix(data, []byte("\xEF\xBB\xBF")))
+	if bytes.HasPrefix(trimmed, []byte("<")) {
+		return ParseCAMT(data)
+	}
+	return ParseMT940(data)
+}
+
+// decodeText returns data as UTF-8
+// decoding it as Windows-1252 if it is not valid UTF-8
+func decodeText(data []byte) ([]byte, error) {
+	if utf8.Valid(data) {
+		return data, nil
+	}
+	return charset.MustGetEncoding("Windows 1252").Decode(data)
+}
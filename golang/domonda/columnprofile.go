@@ -0,0 +1,134 @@
+package domonda
+
+import (
+	"maps"
+	"reflect"
+)
+
+// ColumnProfile maps the column names of the exports
+// of an accounting system to the field names
+// of the master data types for reading CSV and XLSX files.
+// Column names are matched case-insensitive ignoring whitespace and punctuation.
+type ColumnProfile struct {
+	// Name of the profile like "BMD"
+	Name string
+
+	Partner          map[string]string
+	GLAccount        map[string]string
+	BankAccount      map[string]string
+	RealEstateObject map[string]string
+}
+
+// columns returns the column mapping of the profile for structType
+// merged with custom which takes precedence.
+// The profile p can be nil.
+func (p *ColumnProfile) columns(structType reflect.Type, custom map[string]string) map[string]string {
+	var profile map[string]string
+	if p != nil {
+		switch structType {
+		case reflect.TypeFor[Partner](), reflect.TypeFor[partnerExportRow]():
+			profile = p.Partner
+		case reflect.TypeFor[GLAccount](), reflect.TypeFor[accountPlanRow]():
+			profile = p.GLAccount
+		case reflect.TypeFor[BankAccount]():
+			profile = p.BankAccount
+		case reflect.TypeFor[RealEstateObject]():
+			profile = p.RealEstateObject
+		}
+	}
+	if len(profile) == 0 {
+		return custom
+	}
+	merged := make(map[string]string, len(profile)+len(custom))
+	maps.Copy(merged, profile)
+	maps.Copy(merged, custom)
+	return merged
+}
+
+var (
+	// ColumnProfileBMD maps the column names
+	// of BMD NTCS exports (Austria)
+	ColumnProfileBMD = &ColumnProfile{
+		Name: "BMD",
+		Partner: map[string]string{
+			"Name 2":        "AlternativeNames",
+			"UID-Nr":        "VATIDNo",
+			"Firmenbuch-Nr": "CompRegNo",
+			"Tel1":          "Phone",
+			"E-Mail1":       "Email",
+		},
+		GLAccount: map[string]string{
+			"Konto-Nr":         "Number",
+			"Kontobezeichnung": "Name",
+			"Kontoklasse":      "Category",
+		},
+		BankAccount: map[string]string{
+			"Bankkonto":    "Name",
+			"Kontoinhaber": "Holder",
+			"Währung":      "Currency",
+			"Konto-Nr":     "AccountNumber",
+		},
+		RealEstateObject: map[string]string{
+			"Objekt-Nr":         "Number",
+			"Objektart":         "Type",
+			"Objektbezeichnung": "Description",
+			"Buchungskreis":     "AccountingArea",
+			"Straße":            "StreetAddress",
+		},
+	}
+
+	// ColumnProfileRZL maps the column names
+	// of RZL exports (Austria)
+	ColumnProfileRZL = &ColumnProfile{
+		Name: "RZL",
+		Partner: map[string]string{
+			"Bezeichnung":  "Name",
+			"Bezeichnung2": "AlternativeNames",
+			"UID":          "VATIDNo",
+			"Telefon 1":    "Phone",
+			"E-Mail 1":     "Email",
+		},
+		GLAccount: map[string]string{
+			"Kontonummer":      "Number",
+			"Kontobezeichnung": "Name",
+			"Kontengruppe":     "Category",
+			"Objekt":           "ObjectNo",
+		},
+		BankAccount: map[string]string{
+			"Bezeichnung": "Name",
+			"Inhaber":     "Holder",
+			"WKZ":         "Currency",
+			"Kontonummer": "AccountNumber",
+		},
+		RealEstateObject: map[string]string{
+			"Objekt":         "Number",
+			"Objekttyp":      "Type",
+			"Bezeichnung":    "Description",
+			"Rechnungskreis": "AccountingArea",
+			"Adresse":        "StreetAddress",
+		},
+	}
+
+	// ColumnProfileDATEV maps the column names
+	// of DATEV exports (Germany)
+	ColumnProfileDATEV = &ColumnProfile{
+		Name: "DATEV",
+		GLAccount: map[string]string{
+			"Konto":              "Number",
+			"Kontenbeschriftung": "Name",
+			"Beschriftung":       "Name",
+			"Kontenklasse":       "Category",
+		},
+		BankAccount: map[string]string{
+			"Bezeichnung":  "Name",
+			"Kontoinhaber": "Holder",
+			"WKZ":          "Currency",
+			"Bankkonto":    "AccountNumber",
+		},
+		RealEstateObject: map[string]string{
+			"Objektnummer": "Number",
+			"Objekttyp":    "Type",
+			"Bezeichnung":  "Description",
+		},
+	}
+)
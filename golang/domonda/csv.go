@@ -0,0 +1,159 @@
+package domonda
+
+import (
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/domonda/go-types/charset"
+)
+
+// CSVConfig configures how master data is read from CSV files.
+// The zero value detects the delimiter and encoding
+// and uses the default column names.
+type CSVConfig struct {
+	// Delimiter between the columns or zero to detect
+	// one of ',', ';', '\t', or '|' from the header line
+	Delimiter rune
+
+	// Encoding name like "UTF-8" or "Windows-1252"
+	// as supported by github.com/domonda/go-types/charset,
+	// or empty to use the BOM or UTF-8 if the data is valid UTF-8
+	// and Windows-1252 otherwise
+	Encoding string
+
+	// Profile is an optional column mapping of an accounting system
+	// like ColumnProfileBMD that takes precedence over the default column names
+	Profile *ColumnProfile
+
+	// Columns maps CSV header names to struct field names.
+	// Header names are matched case-insensitive ignoring whitespace
+	// and punctuation and take precedence over Profile
+	// and the default column names.
+	Columns map[string]string
+}
+
+// readCSV reads the items of type T from CSV data
+// using the columns of config and defaults.
+// See readTableRows for finalize.
+func readCSV[T any](r io.Reader, config *CSVConfig, defaults map[string][]string, finalize func(*T) error) (items []*T, rowErrs []*RowError, err error) {
+	if config == nil {
+		config = new(CSVConfig)
+	}
+	header, rows, err := readCSVTable(r, config)
+	if err != nil {
+		return nil, nil, err
+	}
+	return readTableRows(header, rows, defaults, config.Profile, config.Columns, finalize)
+}
+
+// readCSVTable reads the header and data rows from CSV data
+// decoded and split as configured by config.
+func readCSVTable(r io.Reader, config *CSVConfig) (header []string, rows []tableRow, err error) {
+	if config == nil {
+		config = new(CSVConfig)
+	}
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, nil, err
+	}
+	data, err = decodeCSV(data, config.Encoding)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	reader := csv.NewReader(bytes.NewReader(data))
+	reader.Comma = config.Delimiter
+	if reader.Comma == 0 {
+		reader.Comma = detectCSVDelimiter(data)
+	}
+	reader.FieldsPerRecord = -1
+	reader.LazyQuotes = true
+
+	header, err = reader.Read()
+	if err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil, nil, errors.New("CSV has no header line")
+		}
+		return nil, nil, err
+	}
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, nil, err
+		}
+		line, _ := reader.FieldPos(0)
+		rows = append(rows, tableRow{Line: line, Values: record})
+	}
+	return header, rows, nil
+}
+
+// decodeCSV returns data decoded as UTF-8 without BOM.
+// See CSVConfig.Encoding for how the encoding is detected
+// if encoding is empty.
+func decodeCSV(data []byte, encoding string) ([]byte, error) {
+	if encoding != "" {
+		enc, err := charset.GetEncoding(encoding)
+		if err != nil {
+			// charset names the code pages like "Windows 1252"
+			// so also try the common "Windows-1252" spelling
+			e, e2 := charset.GetEncoding(strings.ReplaceAll(encoding, "-", " "))
+			if e2 != nil {
+				return nil, err
+			}
+			enc = e
+		}
+		data, err = enc.Decode(data)
+		if err != nil {
+			return nil, fmt.Errorf("can't decode CSV as %s: %w", enc.Name(), err)
+		}
+		_, data = charset.SplitBOM(data)
+		return data, nil
+	}
+
+	if bom, rest := charset.SplitBOM(data); bom != charset.NoBOM {
+		return bom.Decode(rest)
+	}
+	if utf8.Valid(data) {
+		return data, nil
+	}
+	return charset.MustGetEncoding("Windows 1252").Decode(data)
+}
+
+// detectCSVDelimiter returns the most frequent of the characters
+// ',', ';', '\t', or '|' outside of quotes in the first line of data
+// or ',' if none of them was found.
+func detectCSVDelimiter(data []byte) rune {
+	counts := make(map[byte]int)
+	inQuotes := false
+	for _, c := range data {
+		if c == '"' {
+			inQuotes = !inQuotes
+			continue
+		}
+		if inQuotes {
+			continue
+		}
+		if c == '\n' {
+			break
+		}
+		switch c {
+		case ',', ';', '\t', '|':
+			counts[c]++
+		}
+	}
+	delimiter, maxCount := byte(','), 0
+	for _, c := range []byte{',', ';', '\t', '|'} {
+		if counts[c] > maxCount {
+			delimiter, maxCount = c, counts[c]
+		}
+	}
+	return rune(delimiter)
+}
@@ -0,0 +1,227 @@
+package domonda
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"math"
+	"slices"
+
+	"github.com/domonda/go-types/date"
+	"github.com/domonda/go-types/money"
+)
+
+// RateProvider provides currency exchange rates
+type RateProvider interface {
+	// Rate returns the rate to multiply amounts in the currency from
+	// with to get the amounts in the currency to at the date at,
+	// and the date of the rate which may be before at
+	// if there is no rate for at like on weekends.
+	Rate(from, to money.Currency, at date.Date) (rate money.Rate, rateDate date.Date, err error)
+}
+
+// conversionRate returns the rate of provider to convert
+// from the currency of the invoice to the currency to
+// at the invoice date
+func (inv *Invoice) conversionRate(to money.Currency, rates RateProvider) (rate money.Rate, rateDate date.Date, err error) {
+	if inv.Currency.IsNull() {
+		return 0, "", errors.New("invoice has no currency")
+	}
+	if inv.InvoiceDate.IsNull() {
+		return 0, "", errors.New("invoice has no invoice date for the conversion rate")
+	}
+	rate, rateDate, err = rates.Rate(inv.Currency.Get(), to, inv.InvoiceDate.Get())
+	if err != nil {
+		return 0, "", err
+	}
+	if !(rate > 0) {
+		return 0, "", fmt.Errorf("invalid rate %f from %s to %s", rate, inv.Currency.Get(), to)
+	}
+	return rate, rateDate, nil
+}
+
+// SetConversionRate sets the ConversionRate and ConversionRateDate
+// of the invoice to the rate of provider at the InvoiceDate
+// to convert the amounts of the invoice to the bookingCurrency
+// of the company which is the currency of the CostCenters amounts.
+// Both are set to null if the invoice already has the bookingCurrency.
+func (inv *Invoice) SetConversionRate(bookingCurrency money.Currency, rates RateProvider) error {
+	if inv.Currency.IsNotNull() && inv.Currency.Get() == bookingCurrency {
+		inv.ConversionRate = nil
+		inv.ConversionRateDate.SetNull()
+		return nil
+	}
+	rate, rateDate, err := inv.conversionRate(bookingCurrency, rates)
+	if err != nil {
+		return err
+	}
+	inv.ConversionRate = &rate
+	inv.ConversionRateDate = rateDate.Nullable()
+	return nil
+}
+
+// InCurrency returns a copy of the invoice with all amounts converted
+// to the target currency using the rate of provider at the InvoiceDate.
+//
+// Net, Total, VATAmounts, and the amounts of the AccountingItems
+// are multiplied by the rate and rounded to cents.
+// If Net plus the VATAmounts sums up to Total in the original currency
+// the largest converted VAT amount is corrected by the rounding difference
+// so that the converted amounts sum up to the converted Total.
+// The same way the largest converted AccountingItems amount of the type
+// "NET" or "TOTAL" is corrected if the items of the type sum up
+// to Net or Total in the original currency.
+//
+// The CostCenters amounts are in the booking currency of the company
+// and are not converted. ConversionRate of the returned invoice
+// is set to convert the converted Net to the same booking amount as
+// the original Net so that the CostCenters stay valid.
+// The booking currency is the original currency if ConversionRate is null,
+// then ConversionRateDate is set to the date of the rate of provider,
+// else the original ConversionRateDate is kept.
+func (inv *Invoice) InCurrency(target money.Currency, rates RateProvider) (*Invoice, error) {
+	if err := target.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid target currency %q: %w", target, err)
+	}
+	converted := inv.clone()
+	if inv.Currency.IsNotNull() && inv.Currency.Get() == target {
+		return converted, nil
+	}
+	rate, rateDate, err := inv.conversionRate(target, rates)
+	if err != nil {
+		return nil, err
+	}
+	convert := func(amount money.Amount) money.Amount {
+		return amount.MultipliedByRate(rate).RoundToCents()
+	}
+	converted.Currency = money.NullableCurrency(target)
+	if inv.Net != nil {
+		converted.Net = convert(*inv.Net).Ptr()
+	}
+	if inv.Total != nil {
+		converted.Total = convert(*inv.Total).Ptr()
+	}
+	for i, amount := range inv.VATAmounts {
+		converted.VATAmounts[i] = float64(convert(money.Amount(amount)))
+	}
+	if inv.Net != nil && inv.Total != nil && len(inv.VATAmounts) > 0 {
+		sum, convertedSum := *inv.Net, *converted.Net
+		for i := range inv.VATAmounts {
+			sum += money.Amount(inv.VATAmounts[i])
+			convertedSum += money.Amount(converted.VATAmounts[i])
+		}
+		if sum.WithinOneCent(*inv.Total) {
+			largest := 0
+			for i, amount := range converted.VATAmounts {
+				if amount > converted.VATAmounts[largest] {
+					largest = i
+				}
+			}
+			diff := (*converted.Total - convertedSum).RoundToCents()
+			converted.VATAmounts[largest] = float64((money.Amount(converted.VATAmounts[largest]) + diff).RoundToCents())
+		}
+	}
+	for _, item := range converted.AccountingItems {
+		item.Amount = convert(item.Amount)
+	}
+	correctAccountingItems(inv.AccountingItems, converted.AccountingItems, "NET", inv.Net, converted.Net)
+	correctAccountingItems(inv.AccountingItems, converted.AccountingItems, "TOTAL", inv.Total, converted.Total)
+	// The original conversion rate converts to the booking currency,
+	// without conversion rate the original currency is the booking currency
+	bookingRate := money.Rate(1)
+	if inv.ConversionRate != nil {
+		bookingRate = *inv.ConversionRate
+	}
+	if (bookingRate / rate).RoundToDecimals(9) == 1 {
+		// The target is the booking currency
+		converted.ConversionRate = nil
+		converted.ConversionRateDate.SetNull()
+		return converted, nil
+	}
+	conversionRate := bookingRate / rate
+	if inv.Net != nil && *converted.Net != 0 {
+		// Derive the rate from the rounded converted Net
+		// so that it converts to the same booking amount
+		// the CostCenters are validated against
+		bookingNet := inv.Net.MultipliedByRate(bookingRate)
+		conversionRate = money.Rate(bookingNet / *converted.Net)
+		for bookingNet > 0 && converted.Net.MultipliedByRate(conversionRate) < bookingNet {
+			// Compensate floating point errors of the division
+			conversionRate = money.Rate(math.Nextafter(float64(conversionRate), math.Inf(1)))
+		}
+	}
+	converted.ConversionRate = &conversionRate
+	if inv.ConversionRate == nil {
+		converted.ConversionRateDate = rateDate.Nullable()
+	}
+	// else keep the date of the original rate to the booking currency
+	return converted, nil
+}
+
+// correctAccountingItems corrects the largest of the converted accounting items
+// with amountType by the rounding difference so that they sum up
+// to the converted amount if the original items sum up to the original amount
+func correctAccountingItems(items, convertedItems []*AccountingItem, amountType string, amount, convertedAmount *money.Amount) {
+	if amount == nil || convertedAmount == nil {
+		return
+	}
+	var (
+		sum, convertedSum money.Amount
+		largest           *AccountingItem
+	)
+	for i, item := range items {
+		if item.AmountType != amountType {
+			continue
+		}
+		sum += item.Amount
+		convertedSum += convertedItems[i].Amount
+		if largest == nil || convertedItems[i].Amount.Abs() > largest.Amount.Abs() {
+			largest = convertedItems[i]
+		}
+	}
+	if largest == nil || !sum.WithinOneCent(*amount) {
+		return
+	}
+	diff := (*convertedAmount - convertedSum).RoundToCents()
+	largest.Amount = (largest.Amount + diff).RoundToCents()
+}
+
+// clone returns a copy of the invoice that
+// doesn't share pointers, slices, or maps
+func (inv *Invoice) clone() *Invoice {
+	c := *inv
+	clonePtr := func(a *money.Amount) *money.Amount {
+		if a == nil {
+			return nil
+		}
+		return a.Ptr()
+	}
+	c.Net = clonePtr(inv.Net)
+	c.Total = clonePtr(inv.Total)
+	if inv.CreditMemo != nil {
+		creditMemo := *inv.CreditMemo
+		c.CreditMemo = &creditMemo
+	}
+	if inv.VATPercent != nil {
+		c.VATPercent = inv.VATPercent.Ptr()
+	}
+	if inv.DiscountPercent != nil {
+		c.DiscountPercent = inv.DiscountPercent.Ptr()
+	}
+	if inv.ConversionRate != nil {
+		c.ConversionRate = inv.ConversionRate.Ptr()
+	}
+	c.VATPercentages = slices.Clone(inv.VATPercentages)
+	c.VATAmounts = slices.Clone(inv.VATAmounts)
+	c.CostCenters = maps.Clone(inv.CostCenters)
+	c.DeliveryNoteNumbers = slices.Clone(inv.DeliveryNoteNumbers)
+	if inv.AccountingItems != nil {
+		c.AccountingItems = make([]*AccountingItem, len(inv.AccountingItems))
+		for i, item := range inv.AccountingItems {
+			itemCopy := *item
+			itemCopy.ValueAddedTaxPercentageAmount = clonePtr(item.ValueAddedTaxPercentageAmount)
+			c.AccountingItems[i] = &itemCopy
+		}
+	}
+	return &c
+}
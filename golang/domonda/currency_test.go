@@ -0,0 +1,253 @@
+package domonda
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/domonda/go-types/date"
+	"github.com/domonda/go-types/money"
+	"github.com/domonda/go-types/nullable"
+)
+
+// testRates is a RateProvider with fixed rates
+// published the day before the requested date
+type testRates map[[2]money.Currency]money.Rate
+
+func (r testRates) Rate(from, to money.Currency, at date.Date) (money.Rate, date.Date, error) {
+	rate, ok := r[[2]money.Currency{from, to}]
+	if !ok {
+		return 0, "", fmt.Errorf("no rate from %s to %s", from, to)
+	}
+	return rate, at.AddDays(-1), nil
+}
+
+func TestInvoiceInCurrency(t *testing.T) {
+	rates := testRates{
+		{"USD", "EUR"}: 0.9234,
+		{"USD", "CHF"}: 0.8812,
+		{"EUR", "CHF"}: 0.9543,
+	}
+	for _, tt := range []struct {
+		name     string
+		invoice  Invoice
+		target   money.Currency
+		wantNet  money.Amount
+		wantVAT  []float64
+		wantTot  money.Amount
+		wantRate bool // ConversionRate not null
+		wantDate date.NullableDate
+	}{
+		{
+			// 100.00 * 0.9234 = 92.34 and the inverse rate
+			// rounded would convert back to 99.995
+			name: "cost centers in original currency",
+			invoice: Invoice{
+				Currency:    "USD",
+				InvoiceDate: "2024-03-05",
+				Net:         money.Amount(100).Ptr(),
+				Total:       money.Amount(100).Ptr(),
+				CostCenters: map[string]money.Amount{"A": 60, "B": 40},
+			},
+			target:   "EUR",
+			wantNet:  92.34,
+			wantTot:  92.34,
+			wantRate: true,
+			wantDate: "2024-03-04",
+		},
+		{
+			// 10.05 * 1.19 = 11.96 (11.9595) with converted
+			// 9.28 (9.28017) + 1.77 (1.76494) = 11.05 != 11.04 (11.04386)
+			name: "VAT corrected to converted total",
+			invoice: Invoice{
+				Currency:    "USD",
+				InvoiceDate: "2024-03-05",
+				Net:         money.Amount(10.05).Ptr(),
+				VATAmounts:  nullable.FloatArray{1.91},
+				Total:       money.Amount(11.96).Ptr(),
+				CostCenters: map[string]money.Amount{"A": 10.05},
+			},
+			target:   "EUR",
+			wantNet:  9.28,
+			wantVAT:  []float64{1.76},
+			wantTot:  11.04,
+			wantRate: true,
+			wantDate: "2024-03-04",
+		},
+		{
+			name: "largest of multiple VAT amounts corrected",
+			invoice: Invoice{
+				Currency:    "USD",
+				InvoiceDate: "2024-03-05",
+				Net:         money.Amount(20.10).Ptr(),
+				VATAmounts:  nullable.FloatArray{1.91, 0.70},
+				Total:       money.Amount(22.71).Ptr(),
+			},
+			target:   "EUR",
+			wantNet:  18.56,
+			wantVAT:  []float64{1.76, 0.65},
+			wantTot:  20.97,
+			wantRate: true,
+			wantDate: "2024-03-04",
+		},
+		{
+			name: "keeps original booking rate date",
+			invoice: Invoice{
+				Currency:           "USD",
+				InvoiceDate:        "2024-03-05",
+				Net:                money.Amount(100).Ptr(),
+				Total:              money.Amount(119).Ptr(),
+				VATAmounts:         nullable.FloatArray{19},
+				ConversionRate:     money.Rate(0.9234).Ptr(),
+				ConversionRateDate: "2024-03-01",
+				CostCenters:        map[string]money.Amount{"A": 92.34},
+			},
+			target:   "CHF",
+			wantNet:  88.12,
+			wantVAT:  []float64{16.74},
+			wantTot:  104.86,
+			wantRate: true,
+			wantDate: "2024-03-01",
+		},
+		{
+			name: "to booking currency",
+			invoice: Invoice{
+				Currency:           "USD",
+				InvoiceDate:        "2024-03-05",
+				Net:                money.Amount(100).Ptr(),
+				Total:              money.Amount(100).Ptr(),
+				ConversionRate:     money.Rate(0.9234).Ptr(),
+				ConversionRateDate: "2024-03-04",
+				CostCenters:        map[string]money.Amount{"A": 92.34},
+			},
+			target:  "EUR",
+			wantNet: 92.34,
+			wantTot: 92.34,
+		},
+		{
+			name: "same currency",
+			invoice: Invoice{
+				Currency:    "EUR",
+				InvoiceDate: "2024-03-05",
+				Net:         money.Amount(100).Ptr(),
+				Total:       money.Amount(100).Ptr(),
+			},
+			target:  "EUR",
+			wantNet: 100,
+			wantTot: 100,
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.invoice.Validate(); err != nil {
+				t.Fatalf("invalid test invoice: %s", err)
+			}
+			converted, err := tt.invoice.InCurrency(tt.target, rates)
+			if err != nil {
+				t.Fatalf("InCurrency: %s", err)
+			}
+			if err := converted.Validate(); err != nil {
+				t.Errorf("Validate converted: %s", err)
+			}
+			if converted.Currency.Get() != tt.target {
+				t.Errorf("got currency %s, expected %s", converted.Currency, tt.target)
+			}
+			if *converted.Net != tt.wantNet || *converted.Total != tt.wantTot {
+				t.Errorf("got net %s and total %s, expected %s and %s", *converted.Net, *converted.Total, tt.wantNet, tt.wantTot)
+			}
+			if fmt.Sprint(converted.VATAmounts) != fmt.Sprint(nullable.FloatArray(tt.wantVAT)) {
+				t.Errorf("got VAT amounts %v, expected %v", converted.VATAmounts, tt.wantVAT)
+			}
+			if (converted.ConversionRate != nil) != tt.wantRate || converted.ConversionRateDate != tt.wantDate {
+				t.Errorf("got conversion rate %v at %q, expected rate %t at %q", converted.ConversionRate, converted.ConversionRateDate, tt.wantRate, tt.wantDate)
+			}
+			if converted.ConversionRate != nil && tt.invoice.CostCenters != nil {
+				// The converted Net converts to the original booking Net
+				bookingNet := *tt.invoice.Net
+				if tt.invoice.ConversionRate != nil {
+					bookingNet = bookingNet.MultipliedByRate(*tt.invoice.ConversionRate)
+				}
+				if got := converted.Net.MultipliedByRate(*converted.ConversionRate); !got.WithinOneCent(bookingNet) || got < bookingNet {
+					t.Errorf("converted net converts to %f booking net, expected %f", got, bookingNet)
+				}
+			}
+		})
+	}
+}
+
+func TestInvoiceInCurrencyCostCenters(t *testing.T) {
+	rates := testRates{{"USD", "EUR"}: 0.9234}
+	for cents := int64(1); cents <= 1000000; cents += 997 {
+		net := money.Amount(cents) / 100
+		inv := &Invoice{
+			Currency:    "USD",
+			InvoiceDate: "2024-03-05",
+			Net:         net.Ptr(),
+			Total:       net.Ptr(),
+			CostCenters: map[string]money.Amount{"A": net},
+		}
+		converted, err := inv.InCurrency("EUR", rates)
+		if err != nil {
+			t.Fatalf("InCurrency(%s): %s", net, err)
+		}
+		if err := converted.Validate(); err != nil {
+			t.Errorf("net %s converted to %s: %s", net, *converted.Net, err)
+		}
+	}
+}
+
+func TestInvoiceInCurrencyAccountingItems(t *testing.T) {
+	rates := testRates{{"USD", "EUR"}: 0.9234}
+	item := func(amountType string, amount money.Amount) *AccountingItem {
+		return &AccountingItem{AmountType: amountType, Amount: amount}
+	}
+	inv := &Invoice{
+		Currency:    "USD",
+		InvoiceDate: "2024-03-05",
+		Net:         money.Amount(100).Ptr(),
+		VATAmounts:  nullable.FloatArray{20},
+		Total:       money.Amount(120).Ptr(),
+		AccountingItems: []*AccountingItem{
+			// 30.78 + 30.78 + 30.79 = 92.35 != 92.34
+			item("NET", 33.33),
+			item("TOTAL", 39.9),
+			item("NET", 33.34),
+			item("TOTAL", 40.2),
+			item("NET", 33.33),
+			// 36.84 + 37.12 + 36.84 = 110.80 != 110.81
+			item("TOTAL", 39.9),
+		},
+	}
+	converted, err := inv.InCurrency("EUR", rates)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []money.Amount{30.78, 36.84, 30.78, 37.13, 30.78, 36.84}
+	for i, item := range converted.AccountingItems {
+		if item.Amount != want[i] {
+			t.Errorf("AccountingItems[%d].Amount = %s, expected %s", i, item.Amount, want[i])
+		}
+	}
+	sums := make(map[string]money.Amount)
+	for _, item := range converted.AccountingItems {
+		sums[item.AmountType] += item.Amount
+	}
+	if sums["NET"].RoundToCents() != *converted.Net {
+		t.Errorf("NET items sum up to %s instead of Net %s", sums["NET"], *converted.Net)
+	}
+	if sums["TOTAL"].RoundToCents() != *converted.Total {
+		t.Errorf("TOTAL items sum up to %s instead of Total %s", sums["TOTAL"], *converted.Total)
+	}
+	// The original items are not changed
+	if inv.AccountingItems[2].Amount != 33.34 {
+		t.Errorf("original item changed to %s", inv.AccountingItems[2].Amount)
+	}
+
+	// Items that don't sum up to the original amount are not corrected
+	inv.AccountingItems = []*AccountingItem{item("NET", 33.33), item("NET", 33.34)}
+	converted, err = inv.InCurrency("EUR", rates)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if converted.AccountingItems[0].Amount != 30.78 || converted.AccountingItems[1].Amount != 30.79 {
+		t.Errorf("unexpected corrected items %s, %s", converted.AccountingItems[0].Amount, converted.AccountingItems[1].Amount)
+	}
+}
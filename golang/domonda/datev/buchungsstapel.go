@@ -0,0 +1,344 @@
+package datev
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/domonda/go-types/account"
+	"github.com/domonda/go-types/date"
+	"github.com/domonda/go-types/money"
+
+	"github.com/domonda/api/golang/domonda"
+)
+
+// Booking is a booking line of a DATEV EXTF "Buchungsstapel" (booking batch).
+type Booking struct {
+	// Amount is the positive amount of the booking (Umsatz)
+	Amount money.Amount
+
+	// DebitCredit is "S" if Account is debited (Soll)
+	// or "H" if Account is credited (Haben)
+	DebitCredit string
+
+	// Currency of Amount (WKZ Umsatz) or empty for the currency of the header
+	Currency money.NullableCurrency
+
+	// ExchangeRate of Currency (Kurs) or nil
+	ExchangeRate *money.Rate
+
+	// Account is the account of the booking (Konto),
+	// usually the personal account of the partner
+	Account account.Number
+
+	// ContraAccount is the contra account (Gegenkonto),
+	// usually the general ledger account
+	ContraAccount account.Number
+
+	// TaxKey is the DATEV tax key (BU-Schlüssel) or empty
+	TaxKey string
+
+	// DocumentDate is the date of the document (Belegdatum)
+	DocumentDate date.Date
+
+	// DocumentField1 is usually the invoice number (Belegfeld 1)
+	DocumentField1 string
+
+	// DocumentField2 is usually the due date as DDMMYY (Belegfeld 2)
+	DocumentField2 string
+
+	// Text of the booking (Buchungstext)
+	Text string
+}
+
+// Validate returns an error if the booking
+// can't be written to a DATEV EXTF file
+func (b *Booking) Validate() error {
+	var errs []error
+	if !b.Amount.ValidAndGreaterZero() || b.Amount >= 1e10 {
+		errs = append(errs, fmt.Errorf("Umsatz %s not in range of (0..9999999999,99]", b.Amount))
+	}
+	if b.DebitCredit != "S" && b.DebitCredit != "H" {
+		errs = append(errs, fmt.Errorf("Soll/Haben-Kennzeichen must be S or H but is %q", b.DebitCredit))
+	}
+	if !b.Currency.Valid() {
+		errs = append(errs, fmt.Errorf("invalid WKZ Umsatz %q", b.Currency))
+	}
+	if b.ExchangeRate != nil && *b.ExchangeRate <= 0 {
+		errs = append(errs, fmt.Errorf("Kurs must be greater zero, but is %f", *b.ExchangeRate))
+	}
+	if err := validateAccountNumber(b.Account); err != nil {
+		errs = append(errs, fmt.Errorf("Konto %q: %w", b.Account, err))
+	}
+	if err := validateAccountNumber(b.ContraAccount); err != nil {
+		errs = append(errs, fmt.Errorf("Gegenkonto %q: %w", b.ContraAccount, err))
+	}
+	if len(b.TaxKey) > 4 || strings.Trim(b.TaxKey, "0123456789") != "" {
+		errs = append(errs, fmt.Errorf("BU-Schlüssel %q is not a number with up to 4 digits", b.TaxKey))
+	}
+	if err := b.DocumentDate.Validate(); err != nil {
+		errs = append(errs, fmt.Errorf("Belegdatum: %w", err))
+	}
+	if utf8.RuneCountInString(b.DocumentField1) > 36 {
+		errs = append(errs, fmt.Errorf("Belegfeld 1 %q longer than 36 characters", b.DocumentField1))
+	}
+	if strings.Trim(b.DocumentField1, documentField1Chars) != "" {
+		errs = append(errs, fmt.Errorf("Belegfeld 1 %q contains other characters than %q", b.DocumentField1, documentField1Chars))
+	}
+	if utf8.RuneCountInString(b.DocumentField2) > 12 {
+		errs = append(errs, fmt.Errorf("Belegfeld 2 %q longer than 12 characters", b.DocumentField2))
+	}
+	if utf8.RuneCountInString(b.Text) > 60 {
+		errs = append(errs, fmt.Errorf("Buchungstext %q longer than 60 characters", b.Text))
+	}
+	return errors.Join(errs...)
+}
+
+// documentField1Chars are the characters allowed in Belegfeld 1
+const documentField1Chars = "ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz0123456789$%&*+-/"
+
+func validateAccountNumber(number account.Number) error {
+	switch {
+	case number == "":
+		return errors.New("missing account number")
+	case len(number) > 9 || strings.Trim(string(number), "0123456789") != "":
+		return errors.New("not a number with up to 9 digits")
+	}
+	return nil
+}
+
+// bookingColumns are the columns written by WriteBuchungsstapel,
+// the leading columns of the EXTF Buchungsstapel format
+var bookingColumns = []string{
+	"Umsatz (ohne Soll/Haben-Kz)",
+	"Soll/Haben-Kennzeichen",
+	"WKZ Umsatz",
+	"Kurs",
+	"Basis-Umsatz",
+	"WKZ Basis-Umsatz",
+	"Konto",
+	"Gegenkonto (ohne BU-Schlüssel)",
+	"BU-Schlüssel",
+	"Belegdatum",
+	"Belegfeld 1",
+	"Belegfeld 2",
+	"Skonto",
+	"Buchungstext",
+}
+
+// InvoiceBookings returns the bookings of the AccountingItems of the invoices.
+//
+// The Account of the bookings is the personal account of the invoice
+// from Invoice.PartnerNumber and the ContraAccount is the
+// GeneralLedgerAccountNumber of the accounting item.
+// DebitCredit is relative to Account and thus the opposite
+// of the BookingType of the accounting item:
+// "DEBIT" results in "H" and "CREDIT" in "S".
+//
+// The TaxKey is the DATEV code of the VAT code of the accounting item
+// from the catalogue returned by domonda.VATCodes for the VAT percentage.
+// NET amounts are converted to gross amounts using the VAT percentage
+// unless the VAT code is NetOnly, because DATEV calculates the tax
+// from the gross amount. NET amounts with a VAT percentage
+// but without VAT code result in an error, because without
+// TaxKey the VAT would be booked onto the ContraAccount.
+//
+// The DocumentDate is the InvoiceDate, DocumentField1 the InvoiceNumber
+// or InternalNumber, and DocumentField2 the DueDate as DDMMYY.
+// All errors of the invoices are returned joined.
+func InvoiceBookings(invoices []*domonda.Invoice) (bookings []*Booking, err error) {
+	var errs []error
+	for i, inv := range invoices {
+		for j, item := range inv.AccountingItems {
+			booking, err := invoiceBooking(inv, item)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("invoice %d accounting item %d: %w", i, j, err))
+				continue
+			}
+			bookings = append(bookings, booking)
+		}
+	}
+	return bookings, errors.Join(errs...)
+}
+
+func invoiceBooking(inv *domonda.Invoice, item *domonda.AccountingItem) (*Booking, error) {
+	if inv.PartnerNumber.IsNull() {
+		return nil, errors.New("missing PartnerNumber for Konto")
+	}
+	if inv.InvoiceDate.IsNull() {
+		return nil, errors.New("missing InvoiceDate for Belegdatum")
+	}
+	booking := &Booking{
+		Amount:         item.Amount,
+		Currency:       inv.Currency,
+		Account:        account.Number(inv.PartnerNumber.Get()),
+		ContraAccount:  item.GeneralLedgerAccountNumber,
+		DocumentDate:   inv.InvoiceDate.Get(),
+		DocumentField1: inv.InvoiceNumber.String(),
+		Text:           string(item.Title),
+	}
+	if inv.InvoiceNumber.IsNull() {
+		booking.DocumentField1 = inv.InternalNumber.String()
+	}
+	if inv.ConversionRate != nil {
+		booking.ExchangeRate = inv.ConversionRate
+	}
+	if inv.DueDate.IsNotNull() {
+		booking.DocumentField2 = inv.DueDate.Get().Format("020106")
+	}
+	switch item.BookingType {
+	case "DEBIT":
+		booking.DebitCredit = "H"
+	case "CREDIT":
+		booking.DebitCredit = "S"
+	default:
+		return nil, fmt.Errorf("invalid BookingType %q", item.BookingType)
+	}
+
+	var percent *float64
+	if item.ValueAddedTaxPercentageAmount != nil {
+		p := float64(*item.ValueAddedTaxPercentageAmount)
+		percent = &p
+	}
+	netOnly := false
+	if item.ValueAddedTaxID.IsNotNull() {
+		vatCode := domonda.VATCodeByID(item.ValueAddedTaxID.Get())
+		if vatCode == nil {
+			return nil, fmt.Errorf("unknown VAT code %s", item.ValueAddedTaxID)
+		}
+		code := vatCode.SystemCode("DATEV", percent)
+		if code == nil {
+			return nil, fmt.Errorf("VAT code %q has no DATEV BU-Schlüssel", vatCode.Name)
+		}
+		booking.TaxKey = strings.TrimRight(code.Code, "r")
+		netOnly = vatCode.NetOnly
+	}
+	switch item.AmountType {
+	case "TOTAL":
+	case "NET":
+		if percent != nil && *percent != 0 && booking.TaxKey == "" {
+			return nil, fmt.Errorf("NET amount with VAT percentage %g but without VAT code", *percent)
+		}
+		if percent != nil && !netOnly {
+			booking.Amount += booking.Amount.Percentage(*percent)
+		}
+	default:
+		return nil, fmt.Errorf("invalid AmountType %q", item.AmountType)
+	}
+	if booking.Amount < 0 {
+		// Book negative amounts on the other side
+		booking.Amount = -booking.Amount
+		if booking.DebitCredit == "S" {
+			booking.DebitCredit = "H"
+		} else {
+			booking.DebitCredit = "S"
+		}
+	}
+	booking.Amount = booking.Amount.RoundToCents()
+
+	return booking, booking.Validate()
+}
+
+// WriteBuchungsstapel writes the bookings as DATEV EXTF "Buchungsstapel"
+// (booking batch) file encoded as Windows-1252 to w.
+//
+// The header must have the ConsultantNo, ClientNo, and FiscalYearStart set.
+// The format fields of the header are set by this function,
+// Created defaults to the current time, AccountLength to 4, BookingType to 1
+// (financial accounting), and Currency to "EUR".
+// DateFrom and DateTo default to the first and last DocumentDate of the bookings.
+// The header and all bookings are validated before writing
+// and every DocumentDate must be within the period of the header.
+//
+// Only the leading columns of the format from "Umsatz" to "Buchungstext"
+// are written.
+func WriteBuchungsstapel(w io.Writer, header Header, bookings []*Booking) error {
+	header.Format = "EXTF"
+	header.Version = 700
+	header.Category = FormatCategoryBookings
+	header.Name = "Buchungsstapel"
+	header.FormatVersion = 13
+	if header.Created.IsZero() {
+		header.Created = time.Now()
+	}
+	if header.AccountLength == 0 {
+		header.AccountLength = 4
+	}
+	if header.BookingType == 0 {
+		header.BookingType = 1
+	}
+	if header.Currency == "" {
+		header.Currency = "EUR"
+	}
+	if header.DateFrom.IsNull() || header.DateTo.IsNull() {
+		var from, to date.Date
+		for _, b := range bookings {
+			if from == "" || b.DocumentDate.Before(from) {
+				from = b.DocumentDate
+			}
+			if to == "" || b.DocumentDate.After(to) {
+				to = b.DocumentDate
+			}
+		}
+		if header.DateFrom.IsNull() {
+			header.DateFrom = from.Nullable()
+		}
+		if header.DateTo.IsNull() {
+			header.DateTo = to.Nullable()
+		}
+	}
+	if err := header.Validate(); err != nil {
+		return err
+	}
+
+	lines := [][]string{header.fields(), bookingColumns}
+	var errs []error
+	for i, b := range bookings {
+		if err := b.Validate(); err != nil {
+			errs = append(errs, fmt.Errorf("booking %d: %w", i, err))
+			continue
+		}
+		if b.DocumentDate.Before(header.DateFrom.Get()) || b.DocumentDate.After(header.DateTo.Get()) {
+			errs = append(errs, fmt.Errorf("booking %d: Belegdatum %s not within %s to %s", i, b.DocumentDate, header.DateFrom, header.DateTo))
+			continue
+		}
+		var rate string
+		if b.ExchangeRate != nil {
+			rate = money.Amount(*b.ExchangeRate).Format(0, ',', 6)
+		}
+		lines = append(lines, []string{
+			b.Amount.Format(0, ',', 2),
+			quote(b.DebitCredit),
+			quote(string(b.Currency)),
+			rate,
+			"",
+			quote(""),
+			string(b.Account),
+			string(b.ContraAccount),
+			quote(b.TaxKey),
+			b.DocumentDate.Format("0201"),
+			quote(b.DocumentField1),
+			quote(b.DocumentField2),
+			"",
+			quote(b.Text),
+		})
+	}
+	if len(errs) > 0 {
+		return errors.Join(errs...)
+	}
+	return writeLines(w, lines)
+}
+
+// WriteInvoiceBuchungsstapel writes the bookings of the AccountingItems
+// of the invoices as returned by InvoiceBookings
+// as DATEV EXTF "Buchungsstapel" file to w using WriteBuchungsstapel.
+func WriteInvoiceBuchungsstapel(w io.Writer, header Header, invoices []*domonda.Invoice) error {
+	bookings, err := InvoiceBookings(invoices)
+	if err != nil {
+		return err
+	}
+	return WriteBuchungsstapel(w, header, bookings)
+}
@@ -0,0 +1,196 @@
+// Package datev reads and writes data in the formats
+// of the German accounting software DATEV
+// and provides the DATEV standard charts of accounts SKR03 and SKR04.
+package datev
+
+//go:generate go tool go-enum $GOFILE
+
+import (
+	"bytes"
+	"context"
+	_ "embed"
+	"encoding/csv"
+	"fmt"
+	"strings"
+
+	"github.com/domonda/go-types/account"
+	"github.com/domonda/go-types/nullable"
+
+	"github.com/domonda/api/golang/domonda"
+)
+
+var (
+	//go:embed skr03.csv
+	skr03CSV []byte
+
+	//go:embed skr04.csv
+	skr04CSV []byte
+)
+
+// Chart is a DATEV standard chart of accounts (Standardkontenrahmen).
+type Chart string //#enum
+
+const (
+	// SKR03 is the process-oriented standard chart of accounts
+	// (Prozessgliederungsprinzip)
+	SKR03 Chart = "SKR03"
+
+	// SKR04 is the standard chart of accounts following
+	// the structure of the balance sheet and the income statement
+	// (Abschlussgliederungsprinzip)
+	SKR04 Chart = "SKR04"
+)
+
+// Valid indicates if c is any of the valid values for Chart
+func (c Chart) Valid() bool {
+	switch c {
+	case
+		SKR03,
+		SKR04:
+		return true
+	}
+	return false
+}
+
+// Validate returns an error if c is none of the valid values for Chart
+func (c Chart) Validate() error {
+	if !c.Valid() {
+		return fmt.Errorf("invalid value %#v for type datev.Chart", c)
+	}
+	return nil
+}
+
+// Enums returns all valid values for Chart
+func (Chart) Enums() []Chart {
+	return []Chart{
+		SKR03,
+		SKR04,
+	}
+}
+
+// EnumStrings returns all valid values for Chart as strings
+func (Chart) EnumStrings() []string {
+	return []string{
+		"SKR03",
+		"SKR04",
+	}
+}
+
+// String implements the fmt.Stringer interface for Chart
+func (c Chart) String() string {
+	return string(c)
+}
+
+// accountClasses are the names of the account classes
+// 0 to 9 of the charts
+var accountClasses = map[Chart][10]string{
+	SKR03: {
+		"Anlage- und Kapitalkonten",
+		"Finanz- und Privatkonten",
+		"Abgrenzungskonten",
+		"Wareneingangs- und Bestandskonten",
+		"Betriebliche Aufwendungen",
+		"",
+		"",
+		"Bestände an Erzeugnissen",
+		"Erlöskonten",
+		"Vortrags-, Kapital- und statistische Konten",
+	},
+	SKR04: {
+		"Anlagevermögen",
+		"Umlaufvermögen",
+		"Eigenkapital",
+		"Fremdkapital",
+		"Betriebliche Erträge",
+		"Betriebliche Aufwendungen",
+		"Betriebliche Aufwendungen",
+		"Weitere Erträge und Aufwendungen",
+		"",
+		"Vortrags-, Kapital- und statistische Konten",
+	},
+}
+
+const (
+	// CategoryDebitors is the category of debitor (client) accounts
+	CategoryDebitors = "Debitoren"
+
+	// CategoryCreditors is the category of creditor (vendor) accounts
+	CategoryCreditors = "Kreditoren"
+)
+
+// Category returns the name of the account class of number
+// like "Betriebliche Aufwendungen" for the SKR03 account "4930".
+// The accountLength is the length of general ledger account
+// numbers (Sachkontenlänge), usually 4.
+// Shorter numbers are padded with leading zeros, longer numbers
+// are personal accounts with CategoryDebitors for numbers
+// starting with 1 to 6 and CategoryCreditors for numbers starting with 7 to 9.
+// An empty string is returned for invalid numbers or an invalid chart.
+func (c Chart) Category(number account.Number, accountLength int) string {
+	num := string(number)
+	if num == "" || strings.Trim(num, "0123456789") != "" {
+		return ""
+	}
+	if accountLength <= 0 {
+		accountLength = 4
+	}
+	if len(num) > accountLength {
+		if num[0] >= '7' {
+			return CategoryCreditors
+		}
+		if num[0] >= '1' {
+			return CategoryDebitors
+		}
+		return ""
+	}
+	num = strings.Repeat("0", accountLength-len(num)) + num
+	return accountClasses[c][num[0]-'0']
+}
+
+// GLAccounts returns the commonly used general ledger accounts
+// of the standard chart with their Category from the account class.
+// The chart is embedded in the package and is not a complete
+// copy of the official DATEV chart of accounts.
+func (c Chart) GLAccounts() ([]*domonda.GLAccount, error) {
+	var data []byte
+	switch c {
+	case SKR03:
+		data = skr03CSV
+	case SKR04:
+		data = skr04CSV
+	default:
+		return nil, c.Validate()
+	}
+	reader := csv.NewReader(bytes.NewReader(data))
+	reader.Comma = ';'
+	records, err := reader.ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("embedded %s chart: %w", c, err)
+	}
+	accounts := make([]*domonda.GLAccount, 0, len(records)-1)
+	for _, record := range records[1:] {
+		number := account.Number(record[0])
+		accounts = append(accounts, &domonda.GLAccount{
+			Number:   number,
+			Name:     nullable.TrimmedString(record[1]),
+			Category: nullable.TrimmedString(c.Category(number, 4)),
+		})
+	}
+	return accounts, nil
+}
+
+// PostChart upserts the general ledger accounts of the chart
+// as returned by Chart.GLAccounts using domonda.PostGLAccounts
+// to onboard a new client company.
+//
+// Arguments:
+//   - apiKey:          API key (bearer token) for the domonda API
+//   - chart:           Standard chart of accounts to post
+//   - source:          Optional name or ID of who did the import
+func PostChart(ctx context.Context, apiKey string, chart Chart, source string) (results []*domonda.ImportGLAccountResult, err error) {
+	accounts, err := chart.GLAccounts()
+	if err != nil {
+		return nil, err
+	}
+	return domonda.PostGLAccounts(ctx, apiKey, accounts, false, false, false, false, source)
+}
@@ -0,0 +1,376 @@
+package datev
+
+import (
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/domonda/go-types/charset"
+	"github.com/domonda/go-types/date"
+)
+
+// FormatCategory is the category of the data in a DATEV EXTF file.
+type FormatCategory int
+
+const (
+	// FormatCategoryDebitorsCreditors is the category of "Debitoren/Kreditoren" files
+	FormatCategoryDebitorsCreditors FormatCategory = 16
+
+	// FormatCategoryAccountLabels is the category of "Kontenbeschriftungen" files
+	FormatCategoryAccountLabels FormatCategory = 20
+
+	// FormatCategoryBookings is the category of "Buchungsstapel" files
+	FormatCategoryBookings FormatCategory = 21
+)
+
+// Header is the first line of a DATEV EXTF file
+// describing the format and the client of the data.
+type Header struct {
+	// Format is "EXTF" for data from external programs
+	// or "DTVF" for data exported by DATEV
+	Format string
+
+	// Version is the version of the header format like 700
+	Version int
+
+	// Category of the data like FormatCategoryAccountLabels
+	Category FormatCategory
+
+	// Name of the format like "Kontenbeschriftungen"
+	Name string
+
+	// FormatVersion is the version of the data format
+	FormatVersion int
+
+	// Created is the time the file was created
+	Created time.Time
+
+	// Origin is a two letter code of the program that created the file
+	Origin string
+
+	// ExportedBy is the name of the user who exported the file
+	ExportedBy string
+
+	// ImportedBy is the name of the user who imported the file
+	ImportedBy string
+
+	// ConsultantNo is the DATEV consultant number (Beraternummer)
+	ConsultantNo int
+
+	// ClientNo is the DATEV client number (Mandantennummer)
+	ClientNo int
+
+	// FiscalYearStart is the first day of the fiscal year (WJ-Beginn)
+	FiscalYearStart date.NullableDate
+
+	// AccountLength is the length of general ledger account numbers (Sachkontenlänge)
+	AccountLength int
+
+	// DateFrom is the first day of the booking period
+	DateFrom date.NullableDate
+
+	// DateTo is the last day of the booking period
+	DateTo date.NullableDate
+
+	// Description of the booking batch (Bezeichnung)
+	Description string
+
+	// Initials of the user (Diktatkürzel)
+	Initials string
+
+	// BookingType is 1 for financial accounting and 2 for annual financial statements
+	BookingType int
+
+	// AccountingPurpose is the accounting purpose (Rechnungslegungszweck)
+	AccountingPurpose int
+
+	// Locked is true if the bookings are locked (Festschreibung)
+	Locked bool
+
+	// Currency is the currency code of the bookings (WKZ)
+	Currency string
+
+	// Chart is the standard chart of accounts (SKR) if known
+	Chart Chart
+}
+
+// extfHeaderFields is the number of fields of the header line
+// of the EXTF format version 700
+const extfHeaderFields = 31
+
+// parseHeader parses the fields of the header line of an EXTF file
+func parseHeader(fields []string) (*Header, error) {
+	if len(fields) < 4 || (fields[0] != "EXTF" && fields[0] != "DTVF") {
+		return nil, errors.New("not a DATEV EXTF file")
+	}
+	for len(fields) < extfHeaderFields {
+		fields = append(fields, "")
+	}
+	var (
+		h    = &Header{Format: fields[0], Name: fields[3], Origin: fields[7], ExportedBy: fields[8], ImportedBy: fields[9], Description: fields[16], Initials: fields[17], Currency: fields[21]}
+		errs []error
+	)
+	parseInt := func(name, s string) int {
+		if s == "" {
+			return 0
+		}
+		i, err := strconv.Atoi(s)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("invalid EXTF header field %s %q", name, s))
+		}
+		return i
+	}
+	parseDate := func(name, s string) date.NullableDate {
+		if s == "" {
+			return date.Null
+		}
+		d, err := date.Parse("20060102", s)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("invalid EXTF header field %s %q", name, s))
+			return date.Null
+		}
+		return d.Nullable()
+	}
+	h.Version = parseInt("Versionsnummer", fields[1])
+	h.Category = FormatCategory(parseInt("Formatkategorie", fields[2]))
+	h.FormatVersion = parseInt("Formatversion", fields[4])
+	if s := fields[5]; len(s) >= 14 {
+		created, err := time.ParseInLocation("20060102150405", s[:14], time.Local)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("invalid EXTF header field Erzeugt am %q", s))
+		}
+		h.Created = created
+	}
+	h.ConsultantNo = parseInt("Beraternummer", fields[10])
+	h.ClientNo = parseInt("Mandantennummer", fields[11])
+	h.FiscalYearStart = parseDate("WJ-Beginn", fields[12])
+	h.AccountLength = parseInt("Sachkontenlänge", fields[13])
+	h.DateFrom = parseDate("Datum vom", fields[14])
+	h.DateTo = parseDate("Datum bis", fields[15])
+	h.BookingType = parseInt("Buchungstyp", fields[18])
+	h.AccountingPurpose = parseInt("Rechnungslegungszweck", fields[19])
+	h.Locked = fields[20] == "1"
+	switch fields[26] {
+	case "03":
+		h.Chart = SKR03
+	case "04":
+		h.Chart = SKR04
+	}
+	return h, errors.Join(errs...)
+}
+
+// readEXTF reads the header, the column names,
+// and the data records of an EXTF file
+// with the 1-based line number of every record.
+func readEXTF(r io.Reader) (header *Header, columns []string, records [][]string, lines []int, err error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, nil, nil, nil, err
+	}
+	data, err = decode(data)
+	if err != nil {
+		return nil, nil, nil, nil, err
+	}
+	reader := csv.NewReader(bytes.NewReader(data))
+	reader.Comma = ';'
+	reader.FieldsPerRecord = -1
+	reader.LazyQuotes = true
+
+	fields, err := reader.Read()
+	if err != nil {
+		if errors.Is(err, io.EOF) {
+			err = errors.New("empty DATEV EXTF file")
+		}
+		return nil, nil, nil, nil, err
+	}
+	header, err = parseHeader(fields)
+	if err != nil {
+		return nil, nil, nil, nil, err
+	}
+	columns, err = reader.Read()
+	if err != nil {
+		if errors.Is(err, io.EOF) {
+			err = errors.New("DATEV EXTF file has no column names")
+		}
+		return nil, nil, nil, nil, err
+	}
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, nil, nil, nil, err
+		}
+		line, _ := reader.FieldPos(0)
+		records = append(records, record)
+		lines = append(lines, line)
+	}
+	return header, columns, records, lines, nil
+}
+
+// decode returns data as UTF-8 without BOM.
+// DATEV files are encoded as Windows-1252
+// if they are not valid UTF-8.
+func decode(data []byte) ([]byte, error) {
+	if bom, rest := charset.SplitBOM(data); bom != charset.NoBOM {
+		return bom.Decode(rest)
+	}
+	if utf8.Valid(data) {
+		return data, nil
+	}
+	return charset.MustGetEncoding("Windows 1252").Decode(data)
+}
+
+// columnIndex returns the index of the column with the passed name
+// compared case-insensitive or -1 if not found.
+func columnIndex(columns []string, name string) int {
+	for i, column := range columns {
+		if strings.EqualFold(strings.TrimSpace(column), name) {
+			return i
+		}
+	}
+	return -1
+}
+
+// Validate returns an error if the header can't be written
+// as header line of an EXTF file
+func (h *Header) Validate() error {
+	var errs []error
+	if h.Format != "EXTF" {
+		errs = append(errs, fmt.Errorf("EXTF header Format must be \"EXTF\" but is %q", h.Format))
+	}
+	if h.ConsultantNo < 1001 || h.ConsultantNo > 9999999 {
+		errs = append(errs, fmt.Errorf("EXTF header ConsultantNo %d not in range of [1001..9999999]", h.ConsultantNo))
+	}
+	if h.ClientNo < 1 || h.ClientNo > 99999 {
+		errs = append(errs, fmt.Errorf("EXTF header ClientNo %d not in range of [1..99999]", h.ClientNo))
+	}
+	if h.AccountLength < 4 || h.AccountLength > 8 {
+		errs = append(errs, fmt.Errorf("EXTF header AccountLength %d not in range of [4..8]", h.AccountLength))
+	}
+	if h.FiscalYearStart.IsNull() {
+		errs = append(errs, errors.New("missing EXTF header FiscalYearStart"))
+	}
+	if h.Category == FormatCategoryBookings {
+		switch {
+		case h.DateFrom.IsNull() || h.DateTo.IsNull():
+			errs = append(errs, errors.New("missing EXTF header DateFrom or DateTo"))
+		case h.DateFrom.After(h.DateTo):
+			errs = append(errs, fmt.Errorf("EXTF header DateFrom %s is after DateTo %s", h.DateFrom, h.DateTo))
+		case h.FiscalYearStart.IsNotNull():
+			start := h.FiscalYearStart.Get()
+			end := start.AddYears(1).AddDays(-1)
+			if h.DateFrom.Get().Before(start) || h.DateTo.Get().After(end) {
+				errs = append(errs, fmt.Errorf("EXTF header period %s to %s is not within the fiscal year %s to %s", h.DateFrom, h.DateTo, start, end))
+			}
+		}
+		if h.BookingType != 1 && h.BookingType != 2 {
+			errs = append(errs, fmt.Errorf("EXTF header BookingType must be 1 or 2 but is %d", h.BookingType))
+		}
+	}
+	if utf8.RuneCountInString(h.Origin) > 2 {
+		errs = append(errs, fmt.Errorf("EXTF header Origin %q longer than 2 characters", h.Origin))
+	}
+	if utf8.RuneCountInString(h.ExportedBy) > 25 {
+		errs = append(errs, fmt.Errorf("EXTF header ExportedBy %q longer than 25 characters", h.ExportedBy))
+	}
+	if utf8.RuneCountInString(h.Description) > 30 {
+		errs = append(errs, fmt.Errorf("EXTF header Description %q longer than 30 characters", h.Description))
+	}
+	if utf8.RuneCountInString(h.Initials) > 2 {
+		errs = append(errs, fmt.Errorf("EXTF header Initials %q longer than 2 characters", h.Initials))
+	}
+	if h.Currency != "" && len(h.Currency) != 3 {
+		errs = append(errs, fmt.Errorf("EXTF header Currency %q is not a 3 letter currency code", h.Currency))
+	}
+	if h.Chart != "" {
+		if err := h.Chart.Validate(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// fields returns the fields of the header line
+// formatted for writing
+func (h *Header) fields() []string {
+	formatDate := func(d date.NullableDate) string {
+		if d.IsNull() {
+			return ""
+		}
+		return d.Get().Format("20060102")
+	}
+	locked := "0"
+	if h.Locked {
+		locked = "1"
+	}
+	var chart string
+	switch h.Chart {
+	case SKR03:
+		chart = quote("03")
+	case SKR04:
+		chart = quote("04")
+	}
+	return []string{
+		quote(h.Format),
+		strconv.Itoa(h.Version),
+		strconv.Itoa(int(h.Category)),
+		quote(h.Name),
+		strconv.Itoa(h.FormatVersion),
+		h.Created.Format("20060102150405") + fmt.Sprintf("%03d", h.Created.Nanosecond()/int(time.Millisecond)),
+		"",
+		quote(h.Origin),
+		quote(h.ExportedBy),
+		quote(h.ImportedBy),
+		strconv.Itoa(h.ConsultantNo),
+		strconv.Itoa(h.ClientNo),
+		formatDate(h.FiscalYearStart),
+		strconv.Itoa(h.AccountLength),
+		formatDate(h.DateFrom),
+		formatDate(h.DateTo),
+		quote(h.Description),
+		quote(h.Initials),
+		strconv.Itoa(h.BookingType),
+		strconv.Itoa(h.AccountingPurpose),
+		locked,
+		quote(h.Currency),
+		"",
+		quote(""),
+		"",
+		"",
+		chart,
+		"",
+		"",
+		"",
+		quote(""),
+	}
+}
+
+// quote returns s in double quotes
+// with double quotes in s escaped by doubling them
+func quote(s string) string {
+	return `"` + strings.ReplaceAll(s, `"`, `""`) + `"`
+}
+
+// writeLines writes the lines of fields separated by semicolons
+// encoded as Windows-1252 with CRLF line endings to w
+func writeLines(w io.Writer, lines [][]string) error {
+	var b strings.Builder
+	for _, fields := range lines {
+		b.WriteString(strings.Join(fields, ";"))
+		b.WriteString("\r\n")
+	}
+	data, err := charset.MustGetEncoding("Windows 1252").Encode([]byte(b.String()))
+	if err != nil {
+		return fmt.Errorf("can't encode DATEV EXTF file as Windows-1252: %w", err)
+	}
+	_, err = w.Write(data)
+	return err
+}
@@ -0,0 +1,91 @@
+package datev
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/domonda/go-types/account"
+	"github.com/domonda/go-types/nullable"
+
+	"github.com/domonda/api/golang/domonda"
+)
+
+// ParseKontenbeschriftungen parses a DATEV EXTF "Kontenbeschriftungen"
+// (account labels) file into general ledger accounts
+// with their Category from the account class of the chart.
+//
+// The chart from the SKR field of the file header takes precedence
+// over the passed chart which is used for files without SKR field.
+// If no chart is known then Category is only set for personal accounts.
+// Numeric general ledger account numbers shorter than the Sachkontenlänge
+// of the header are padded with leading zeros.
+// The "Kontenbeschriftung lang" column is used as Name if not empty.
+// If an account has labels in multiple languages, the German label is used.
+//
+// Rows with invalid accounts are returned as domonda.RowError
+// with their line number instead of as account.
+func ParseKontenbeschriftungen(r io.Reader, chart Chart) (header *Header, accounts []*domonda.GLAccount, rowErrs []*domonda.RowError, err error) {
+	header, columns, records, lines, err := readEXTF(r)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	if header.Category != FormatCategoryAccountLabels {
+		return nil, nil, nil, fmt.Errorf("DATEV EXTF file has format category %d %q instead of %d Kontenbeschriftungen", header.Category, header.Name, FormatCategoryAccountLabels)
+	}
+	if header.Chart != "" {
+		chart = header.Chart
+	}
+	var (
+		numberCol   = columnIndex(columns, "Konto")
+		labelCol    = columnIndex(columns, "Kontenbeschriftung")
+		longCol     = columnIndex(columns, "Kontenbeschriftung lang")
+		languageCol = columnIndex(columns, "Sprach-ID")
+	)
+	if numberCol == -1 || labelCol == -1 {
+		return nil, nil, nil, errors.New(`DATEV EXTF Kontenbeschriftungen file is missing the columns "Konto" and "Kontenbeschriftung"`)
+	}
+	accountLength := header.AccountLength
+	if accountLength <= 0 {
+		accountLength = 4
+	}
+
+	accountIndex := make(map[account.Number]int)
+	for i, record := range records {
+		field := func(col int) string {
+			if col < 0 || col >= len(record) {
+				return ""
+			}
+			return strings.TrimSpace(record[col])
+		}
+		num := field(numberCol)
+		if num == "" {
+			continue
+		}
+		if len(num) < accountLength && strings.Trim(num, "0123456789") == "" {
+			num = strings.Repeat("0", accountLength-len(num)) + num
+		}
+		acc := &domonda.GLAccount{
+			Number:   account.Number(num),
+			Name:     nullable.TrimmedString(field(labelCol)),
+			Category: nullable.TrimmedString(chart.Category(account.Number(num), accountLength)),
+		}
+		if long := field(longCol); long != "" {
+			acc.Name = nullable.TrimmedString(long)
+		}
+		if e := acc.Validate(); e != nil {
+			rowErrs = append(rowErrs, &domonda.RowError{Line: lines[i], Err: e})
+			continue
+		}
+		if index, ok := accountIndex[acc.Number]; ok {
+			if language := field(languageCol); strings.HasPrefix(language, "de") {
+				accounts[index] = acc
+			}
+			continue
+		}
+		accountIndex[acc.Number] = len(accounts)
+		accounts = append(accounts, acc)
+	}
+	return header, accounts, rowErrs, nil
+}
@@ -0,0 +1,130 @@
+package domonda
+
+import (
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strconv"
+
+	"github.com/domonda/go-types/date"
+	"github.com/domonda/go-types/money"
+	"github.com/ungerik/go-fs"
+)
+
+// ECBMaxRateAgeDays is the maximum number of days
+// the date of an ECB rate may be before the requested date
+// to cover weekends and public holidays without published rates.
+const ECBMaxRateAgeDays = 7
+
+// ECBRates are the euro foreign exchange reference rates
+// of the European Central Bank implementing RateProvider.
+//
+// The rates are loaded from the XML files published at
+// https://www.ecb.europa.eu/stats/eurofxref/ like "eurofxref-daily.xml"
+// or "eurofxref-hist.xml" with the rates of all days since 1999.
+// Rates between two other currencies than EUR are calculated
+// as cross rates of the euro rates.
+type ECBRates struct {
+	dates []date.Date // ascending
+	rates map[date.Date]map[money.Currency]money.Rate
+}
+
+type ecbEnvelope struct {
+	Days []struct {
+		Time  string `xml:"time,attr"`
+		Rates []struct {
+			Currency string `xml:"currency,attr"`
+			Rate     string `xml:"rate,attr"`
+		} `xml:"Cube"`
+	} `xml:"Cube>Cube"`
+}
+
+// ParseECBRates parses the euro foreign exchange
+// reference rates from ECB XML data
+func ParseECBRates(r io.Reader) (*ECBRates, error) {
+	var envelope ecbEnvelope
+	if err := xml.NewDecoder(r).Decode(&envelope); err != nil {
+		return nil, fmt.Errorf("can't parse ECB rates XML: %w", err)
+	}
+	e := &ECBRates{rates: make(map[date.Date]map[money.Currency]money.Rate, len(envelope.Days))}
+	var errs []error
+	for _, day := range envelope.Days {
+		d, err := date.Normalize(day.Time)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("invalid ECB rates date %q: %w", day.Time, err))
+			continue
+		}
+		rates := make(map[money.Currency]money.Rate, len(day.Rates))
+		for _, r := range day.Rates {
+			rate, err := strconv.ParseFloat(r.Rate, 64)
+			if err != nil || rate <= 0 {
+				errs = append(errs, fmt.Errorf("invalid ECB rate %q of %s at %s", r.Rate, r.Currency, d))
+				continue
+			}
+			rates[money.Currency(r.Currency)] = money.Rate(rate)
+		}
+		if _, exists := e.rates[d]; !exists {
+			e.dates = append(e.dates, d)
+		}
+		e.rates[d] = rates
+	}
+	if len(e.dates) == 0 {
+		errs = append(errs, errors.New("no ECB rates found"))
+	}
+	slices.Sort(e.dates)
+	return e, errors.Join(errs...)
+}
+
+// LoadECBRatesFile loads the euro foreign exchange
+// reference rates from a local ECB XML file
+func LoadECBRatesFile(file fs.FileReader) (*ECBRates, error) {
+	reader, err := file.OpenReader()
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Close()
+
+	rates, err := ParseECBRates(reader)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", file.Name(), err)
+	}
+	return rates, nil
+}
+
+// DateRange returns the first and last date of the rates
+func (e *ECBRates) DateRange() (first, last date.Date) {
+	if len(e.dates) == 0 {
+		return "", ""
+	}
+	return e.dates[0], e.dates[len(e.dates)-1]
+}
+
+// Rate implements RateProvider with the rates of the latest date
+// not after at with rates for both currencies.
+// Returns an error if that date is more than ECBMaxRateAgeDays before at.
+func (e *ECBRates) Rate(from, to money.Currency, at date.Date) (rate money.Rate, rateDate date.Date, err error) {
+	i, found := slices.BinarySearch(e.dates, at)
+	if !found {
+		i--
+	}
+	for ; i >= 0 && !e.dates[i].Before(at.AddDays(-ECBMaxRateAgeDays)); i-- {
+		rates := e.rates[e.dates[i]]
+		fromRate, fromOK := ecbRate(rates, from)
+		toRate, toOK := ecbRate(rates, to)
+		if fromOK && toOK {
+			return toRate / fromRate, e.dates[i], nil
+		}
+	}
+	return 0, "", fmt.Errorf("no ECB rate from %s to %s at %s", from, to, at)
+}
+
+// ecbRate returns the euro rate of currency
+func ecbRate(rates map[money.Currency]money.Rate, currency money.Currency) (money.Rate, bool) {
+	if currency == money.EUR {
+		return 1, true
+	}
+	rate, ok := rates[currency]
+	return rate, ok
+}
@@ -15,7 +15,7 @@ import (
 
 // GLAccount represents a general ledger account
 type GLAccount struct {
-	Number   account.Number         // Alphanumeric account number
+	Number   account.Number         `jsonschema:"required"` // Alphanumeric account number
 	Name     nullable.TrimmedString // Name of the account
 	Category nullable.TrimmedString // Higher level description of the account
 	ObjectNo account.NullableNumber // Optional real estate object number connected to the account
@@ -35,7 +35,7 @@ func (a *GLAccount) Validate() error {
 type ImportGLAccountResult struct {
 	// General ledger account number after normalization
 	// (e.g. with object number appended if configured)
-	NormalizedNumber account.Number
+	NormalizedNumber account.Number `jsonschema:"required"`
 
 	// ID of the general ledger account that was created or updated
 	ID uu.NullableID `json:",omitzero"`
@@ -44,12 +44,27 @@ type ImportGLAccountResult struct {
 	RealEstateObjectID uu.NullableID `json:",omitzero"`
 
 	// State of the partner after import
-	State ImportState
+	State ImportState `jsonschema:"required"`
 
 	// Error message from the import in case of State "ERROR"
 	Error string `json:",omitempty"`
 }
 
+// ImportState implements the ImportResult interface
+func (r ImportGLAccountResult) ImportState() ImportState {
+	return r.State
+}
+
+// ImportError implements the ImportResult interface
+func (r ImportGLAccountResult) ImportError() string {
+	return r.Error
+}
+
+// ImportItem implements the ImportResult interface
+func (r ImportGLAccountResult) ImportItem() string {
+	return r.NormalizedNumber.String()
+}
+
 // PostGLAccounts upserts general ledger accounts
 // using the API endpoint https://domonda.app/api/public/masterdata/gl-accounts.
 //
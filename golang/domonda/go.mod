@@ -6,6 +6,7 @@ tool github.com/ungerik/go-enum
 
 require (
 	github.com/domonda/go-types v0.0.0-20260327082518-11ac2cfe4cdf
+	github.com/skip2/go-qrcode v0.0.0-20200617195104-da1b6568686e
 	github.com/ungerik/go-fs v0.0.0-20260223082201-e2c085a20e01
 )
 
@@ -0,0 +1,228 @@
+package domonda
+
+import (
+	"cmp"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// ImportResult is implemented by all import result types
+// like ImportPartnerResult, ImportGLAccountResult, or ImportBankAccountResult.
+type ImportResult interface {
+	// ImportState returns the state of the imported item
+	ImportState() ImportState
+
+	// ImportError returns the error message of the import
+	// or an empty string if there was no error
+	ImportError() string
+
+	// ImportItem returns a short description identifying the imported item
+	// like its name or number, or an empty string if not available
+	ImportItem() string
+}
+
+var (
+	_ ImportResult = ImportPartnerResult{}
+	_ ImportResult = ImportGLAccountResult{}
+	_ ImportResult = ImportBankAccountResult{}
+	_ ImportResult = ImportRealEstateObjectResult{}
+	_ ImportResult = ImportObjectTenantOwnerResult{}
+	_ ImportResult = ImportObjectInstanceResult{}
+)
+
+// ImportReport summarizes the results of an import
+// with the counts per ImportState and the errors grouped by message.
+type ImportReport struct {
+	// Title of the report like "Partner import"
+	Title string
+
+	// Total number of import results
+	Total int
+
+	// Counts of the results per ImportState
+	Counts map[ImportState]int
+
+	// ErrorGroups contains the results with State "ERROR"
+	// grouped by error message, sorted by descending count
+	ErrorGroups []*ImportErrorGroup `json:",omitempty"`
+}
+
+// ImportErrorGroup is a group of import results
+// with the same error message.
+type ImportErrorGroup struct {
+	// Error message of the results
+	Error string
+
+	// Items identifying the results with the error,
+	// the result index like "#3" is used for results without ImportItem
+	Items []string
+}
+
+// NewImportReport returns an ImportReport for the results of an import
+// as returned by PostPartners, PostGLAccounts, PostBankAccounts, etc.
+// A nil result pointer is counted as State "ERROR".
+func NewImportReport[R ImportResult](title string, results []R) *ImportReport {
+	report := &ImportReport{
+		Title:  title,
+		Total:  len(results),
+		Counts: make(map[ImportState]int),
+	}
+	for i, result := range results {
+		var (
+			state   ImportState
+			errMsg  string
+			itemStr string
+		)
+		if isNilPointer(result) {
+			state = ImportStateError
+			errMsg = "missing result"
+		} else {
+			state = result.ImportState()
+			errMsg = result.ImportError()
+			itemStr = result.ImportItem()
+		}
+		report.Counts[state]++
+		if state != ImportStateError && errMsg == "" {
+			continue
+		}
+		if itemStr == "" {
+			itemStr = "#" + strconv.Itoa(i)
+		}
+		if errMsg == "" {
+			errMsg = "unknown error"
+		}
+		group := report.errorGroup(errMsg)
+		group.Items = append(group.Items, itemStr)
+	}
+	slices.SortStableFunc(report.ErrorGroups, func(a, b *ImportErrorGroup) int {
+		return cmp.Compare(len(b.Items), len(a.Items))
+	})
+	return report
+}
+
+func isNilPointer(v any) bool {
+	val := reflect.ValueOf(v)
+	return val.Kind() == reflect.Pointer && val.IsNil()
+}
+
+func (r *ImportReport) errorGroup(errMsg string) *ImportErrorGroup {
+	for _, group := range r.ErrorGroups {
+		if group.Error == errMsg {
+			return group
+		}
+	}
+	group := &ImportErrorGroup{Error: errMsg}
+	r.ErrorGroups = append(r.ErrorGroups, group)
+	return group
+}
+
+// HasErrors returns true if any result had State "ERROR"
+// or an error message.
+func (r *ImportReport) HasErrors() bool {
+	return len(r.ErrorGroups) > 0
+}
+
+// String returns the report as plain text
+func (r *ImportReport) String() string {
+	var b strings.Builder
+	_ = r.WriteText(&b)
+	return b.String()
+}
+
+// WriteText writes the report as plain text to w
+func (r *ImportReport) WriteText(w io.Writer) error {
+	var b strings.Builder
+	if r.Title != "" {
+		fmt.Fprintf(&b, "%s\n", r.Title)
+	}
+	fmt.Fprintf(&b, "Total: %d\n", r.Total)
+	for _, state := range ImportState("").Enums() {
+		fmt.Fprintf(&b, "%s: %d\n", state, r.Counts[state])
+	}
+	if len(r.ErrorGroups) > 0 {
+		b.WriteString("Errors:\n")
+		for _, group := range r.ErrorGroups {
+			fmt.Fprintf(&b, "  %dx %s\n", len(group.Items), group.Error)
+			fmt.Fprintf(&b, "    %s\n", strings.Join(group.Items, ", "))
+		}
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// WriteMarkdown writes the report as Markdown to w
+func (r *ImportReport) WriteMarkdown(w io.Writer) error {
+	var b strings.Builder
+	if r.Title != "" {
+		fmt.Fprintf(&b, "## %s\n\n", r.Title)
+	}
+	b.WriteString("| State | Count |\n")
+	b.WriteString("|-------|------:|\n")
+	for _, state := range ImportState("").Enums() {
+		fmt.Fprintf(&b, "| %s | %d |\n", state, r.Counts[state])
+	}
+	fmt.Fprintf(&b, "| **Total** | **%d** |\n", r.Total)
+	if len(r.ErrorGroups) > 0 {
+		b.WriteString("\n### Errors\n\n")
+		b.WriteString("| Count | Error | Items |\n")
+		b.WriteString("|------:|-------|-------|\n")
+		for _, group := range r.ErrorGroups {
+			fmt.Fprintf(&b, "| %d | %s | %s |\n",
+				len(group.Items),
+				escapeMarkdownTableCell(group.Error),
+				escapeMarkdownTableCell(strings.Join(group.Items, ", ")),
+			)
+		}
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+func escapeMarkdownTableCell(s string) string {
+	s = strings.ReplaceAll(s, "|", `\|`)
+	return strings.ReplaceAll(s, "\n", " ")
+}
+
+// WriteCSV writes the report as CSV to w
+// with the columns "Type", "Value", "Count", "Items".
+// The first rows have the Type "STATE" with the ImportState as Value,
+// followed by a row with the Type "TOTAL" and rows with the Type "ERROR"
+// with the error message as Value and the items with that error separated by newlines.
+func (r *ImportReport) WriteCSV(w io.Writer) error {
+	writer := csv.NewWriter(w)
+	err := writer.Write([]string{"Type", "Value", "Count", "Items"})
+	if err != nil {
+		return err
+	}
+	for _, state := range ImportState("").Enums() {
+		err = writer.Write([]string{"STATE", string(state), strconv.Itoa(r.Counts[state]), ""})
+		if err != nil {
+			return err
+		}
+	}
+	err = writer.Write([]string{"TOTAL", "", strconv.Itoa(r.Total), ""})
+	if err != nil {
+		return err
+	}
+	for _, group := range r.ErrorGroups {
+		err = writer.Write([]string{"ERROR", group.Error, strconv.Itoa(len(group.Items)), strings.Join(group.Items, "\n")})
+		if err != nil {
+			return err
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+// WriteJSON writes the report as indented JSON to w
+func (r *ImportReport) WriteJSON(w io.Writer) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(r)
+}
@@ -0,0 +1,87 @@
+// gen-bank-directory generates the bank-directory.csv embedded
+// by domonda.DefaultBankDirectory from the official directories
+// of the Deutsche Bundesbank, the Oesterreichische Nationalbank,
+// and SIX Interbank Clearing.
+//
+// The sources can be URLs or local files. The download URLs
+// of the publishers change with new releases, pass the current
+// ones with the flags if the defaults are outdated:
+//
+//	go run ./internal/gen-bank-directory -bundesbank blz.txt -oenb oenb.csv -six bankmaster.csv
+package main
+
+import (
+	"bytes"
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/domonda/api/golang/domonda"
+)
+
+var (
+	bundesbank = flag.String("bundesbank", "https://www.bundesbank.de/resource/blob/602632/latest/mL/blz-aktuell-txt-data.txt", "URL or file of the Bundesbank Bankleitzahlendatei")
+	oenb       = flag.String("oenb", "https://www.oenb.at/docroot/downloads_observ/sepa-zv-vz_gesamt.csv", "URL or file of the OeNB bank directory CSV")
+	six        = flag.String("six", "https://api.six-group.com/api/epcd/bankmaster/v3/bankmaster_V3.csv", "URL or file of the SIX bank master data CSV")
+	out        = flag.String("out", "bank-directory.csv", "output file")
+)
+
+func main() {
+	flag.Parse()
+
+	dir := domonda.NewBankDirectory()
+	for _, source := range []struct {
+		name string
+		load func(io.Reader) error
+	}{
+		{*bundesbank, dir.LoadBundesbank},
+		{*oenb, dir.LoadOeNB},
+		{*six, dir.LoadSIX},
+	} {
+		data, err := read(source.name)
+		if err != nil {
+			log.Fatal(err)
+		}
+		if err := source.load(bytes.NewReader(data)); err != nil {
+			log.Fatalf("%s: %s", source.name, err)
+		}
+		log.Printf("%d banks after loading %s", dir.Len(), source.name)
+	}
+
+	var buf bytes.Buffer
+	if err := dir.WriteCSV(&buf); err != nil {
+		log.Fatal(err)
+	}
+	if err := os.WriteFile(*out, buf.Bytes(), 0644); err != nil {
+		log.Fatal(err)
+	}
+	log.Printf("%d banks written to %s", dir.Len(), *out)
+}
+
+// read returns the data of a URL or local file
+func read(source string) ([]byte, error) {
+	if !strings.HasPrefix(source, "https://") && !strings.HasPrefix(source, "http://") {
+		return os.ReadFile(source)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, source, nil)
+	if err != nil {
+		return nil, err
+	}
+	response, err := http.DefaultClient.Do(request)
+	if err != nil {
+		return nil, err
+	}
+	defer response.Body.Close()
+	if response.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("%s: unexpected status code: %d", source, response.StatusCode)
+	}
+	return io.ReadAll(response.Body)
+}
@@ -241,3 +241,26 @@ func (inv *Invoice) Validate() error {
 	}
 	return nil
 }
+
+// Discount returns the discount of the Total by DiscountPercent rounded to cents
+// or zero if the invoice has no Total or DiscountPercent.
+func (inv *Invoice) Discount() money.Amount {
+	if inv.Total == nil || inv.DiscountPercent == nil {
+		return 0
+	}
+	return inv.Total.Percentage(float64(*inv.DiscountPercent)).RoundToCents()
+}
+
+// AmountDue returns the amount to pay at paymentDate
+// which is the Total minus the Discount if paymentDate
+// is not after DiscountUntil or DiscountUntil is null.
+// Returns zero if the invoice has no Total.
+func (inv *Invoice) AmountDue(paymentDate date.Date) (amount, discount money.Amount) {
+	if inv.Total == nil {
+		return 0, 0
+	}
+	if inv.DiscountUntil.IsNull() || !paymentDate.After(inv.DiscountUntil.Get()) {
+		discount = inv.Discount()
+	}
+	return (*inv.Total - discount).RoundToCents(), discount
+}
@@ -0,0 +1,122 @@
+package domonda
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"reflect"
+)
+
+// ParseInvoiceJSONC parses an invoice from JSON or JSONC (JSON with comments)
+// as accepted by the upload API for the form field "invoice",
+// see the example file example/invoice.jsonc.
+//
+// Line comments, block comments, and trailing commas are removed
+// before the JSON is decoded strictly according to invoice.schema.json:
+// object member names must match the schema exactly
+// and unknown members are reported as errors with their line and column.
+// After decoding, Invoice.Validate is called on the result.
+//
+// Use this function to lint invoice files before uploading them.
+func ParseInvoiceJSONC(r io.Reader) (*Invoice, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	data = stripJSONC(data)
+
+	err = unknownJSONFields(data, reflect.TypeFor[Invoice]())
+	if err != nil {
+		return nil, err
+	}
+	var inv *Invoice
+	err = json.Unmarshal(data, &inv)
+	if err != nil {
+		return nil, jsonErrorWithPosition(data, err)
+	}
+	if inv == nil {
+		return nil, errors.New("JSON is null instead of an invoice object")
+	}
+	err = inv.Validate()
+	if err != nil {
+		return nil, err
+	}
+	return inv, nil
+}
+
+// stripJSONC converts JSONC to JSON by overwriting
+// line comments, block comments, and trailing commas
+// before closing brackets or braces with spaces.
+// Newlines are kept so that byte offsets, lines and columns
+// of the result are identical to the ones of the JSONC input.
+func stripJSONC(jsonc []byte) []byte {
+	data := bytes.Clone(jsonc)
+
+	// Overwrite comments with spaces
+	inString := false
+	for i := 0; i < len(data); i++ {
+		switch {
+		case inString:
+			switch data[i] {
+			case '\\':
+				i++ // Skip escaped character
+			case '"':
+				inString = false
+			}
+
+		case data[i] == '"':
+			inString = true
+
+		case data[i] == '/' && i+1 < len(data) && data[i+1] == '/':
+			for ; i < len(data) && data[i] != '\n'; i++ {
+				data[i] = ' '
+			}
+
+		case data[i] == '/' && i+1 < len(data) && data[i+1] == '*':
+			data[i], data[i+1] = ' ', ' '
+			for i += 2; i < len(data); i++ {
+				if data[i] == '*' && i+1 < len(data) && data[i+1] == '/' {
+					data[i], data[i+1] = ' ', ' '
+					i++
+					break
+				}
+				if data[i] != '\n' && data[i] != '\r' {
+					data[i] = ' '
+				}
+			}
+		}
+	}
+
+	// Overwrite trailing commas with spaces
+	inString = false
+	for i := 0; i < len(data); i++ {
+		switch {
+		case inString:
+			switch data[i] {
+			case '\\':
+				i++
+			case '"':
+				inString = false
+			}
+
+		case data[i] == '"':
+			inString = true
+
+		case data[i] == ',':
+			next := skipJSONWhitespace(data, i+1)
+			if next < len(data) && (data[next] == '}' || data[next] == ']') {
+				data[i] = ' '
+			}
+		}
+	}
+
+	return data
+}
+
+func skipJSONWhitespace(data []byte, i int) int {
+	for i < len(data) && (data[i] == ' ' || data[i] == '\t' || data[i] == '\r' || data[i] == '\n') {
+		i++
+	}
+	return i
+}
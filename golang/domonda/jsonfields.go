@@ -0,0 +1,366 @@
+package domonda
+
+import (
+	"bytes"
+	"encoding"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+)
+
+var (
+	jsonUnmarshalerType = reflect.TypeFor[json.Unmarshaler]()
+	textUnmarshalerType = reflect.TypeFor[encoding.TextUnmarshaler]()
+)
+
+// unknownJSONFields walks the JSON value in data and returns an error
+// for every object member name that does not exactly match
+// the JSON name of a field of the struct type t or its nested struct types.
+// Errors are prefixed with the line and column of the member name in data.
+// Types that implement json.Unmarshaler or encoding.TextUnmarshaler
+// are not inspected because they define their own JSON format.
+func unknownJSONFields(data []byte, t reflect.Type) error {
+	var errs []error
+	onUnknown := func(member JSONMember, _ string) {
+		errs = append(errs, fmt.Errorf("%s: unknown field %q", member.Position(), member.Path))
+	}
+	err := walkJSONFields(data, t, jsonFieldVisitor{
+		unknown:      onUnknown,
+		caseMismatch: onUnknown,
+	})
+	if err != nil {
+		return err
+	}
+	return errors.Join(errs...)
+}
+
+// JSONMember identifies a member of a JSON object
+// by its path and position in the JSON input.
+type JSONMember struct {
+	// Path of the member like "[2].BankAccounts[0].IBAN"
+	Path string
+
+	// Line is the 1-based line of the member name in the JSON input,
+	// or of the object start for absent members
+	Line int
+
+	// Column is the 1-based byte column of the member name in the JSON input,
+	// or of the object start for absent members
+	Column int
+}
+
+// Position returns the line and column of the member as string
+func (m JSONMember) Position() string {
+	return fmt.Sprintf("line %d column %d", m.Line, m.Column)
+}
+
+func (m JSONMember) String() string {
+	return fmt.Sprintf("%s: %s", m.Position(), m.Path)
+}
+
+// jsonFieldVisitor has callbacks for walkJSONFields.
+// Callbacks that are nil are not called.
+type jsonFieldVisitor struct {
+	// unknown is called for an object member
+	// that matches no field of the struct, not even case-insensitive
+	unknown func(member JSONMember, name string)
+
+	// caseMismatch is called for an object member that only matches
+	// the JSON name fieldName of a struct field case-insensitive,
+	// which is accepted by encoding/json
+	caseMismatch func(member JSONMember, fieldName string)
+
+	// absent is called for every field of a struct
+	// that has no member in its JSON object
+	absent func(member JSONMember, field jsonStructField)
+}
+
+// walkJSONFields walks the JSON value in data
+// expecting it to be decodable into the type t
+// and calls the callbacks of visitor for the member names
+// of JSON objects that are decoded into structs.
+// Types that implement json.Unmarshaler or encoding.TextUnmarshaler
+// are not inspected because they define their own JSON format.
+// Syntax errors are returned with the line and column where they occurred.
+func walkJSONFields(data []byte, t reflect.Type, visitor jsonFieldVisitor) error {
+	w := &jsonFieldWalker{
+		jsonFieldVisitor: visitor,
+		data:             data,
+		dec:              json.NewDecoder(bytes.NewReader(data)),
+	}
+	err := w.walk(t, "")
+	if err != nil {
+		return jsonErrorWithPosition(data, err)
+	}
+	return nil
+}
+
+type jsonFieldWalker struct {
+	jsonFieldVisitor
+	data []byte
+	dec  *json.Decoder
+}
+
+func (w *jsonFieldWalker) member(offset int64, path string) JSONMember {
+	line, col := lineColumn(w.data, offset)
+	return JSONMember{Path: path, Line: line, Column: col}
+}
+
+// walk reads the next JSON value from the decoder.
+// If t is nil then the value is read without checking its member names.
+func (w *jsonFieldWalker) walk(t reflect.Type, path string) error {
+	t = inspectableJSONType(t)
+	start := skipJSONSeparators(w.data, w.dec.InputOffset())
+	tok, err := w.dec.Token()
+	if err != nil {
+		return err
+	}
+	switch tok {
+	case json.Delim('{'):
+		var (
+			fields  []jsonStructField
+			present map[string]bool
+			elem    reflect.Type
+		)
+		if t != nil {
+			switch t.Kind() {
+			case reflect.Struct:
+				fields = jsonStructFields(t)
+				present = make(map[string]bool, len(fields))
+			case reflect.Map:
+				elem = t.Elem()
+			}
+		}
+		for w.dec.More() {
+			offset := skipJSONSeparators(w.data, w.dec.InputOffset())
+			tok, err := w.dec.Token()
+			if err != nil {
+				return err
+			}
+			name, _ := tok.(string)
+			memberPath := joinJSONPath(path, name)
+			memberType := elem
+			if fields != nil {
+				field, exact := matchJSONStructField(fields, name)
+				switch {
+				case field == nil:
+					if w.unknown != nil {
+						w.unknown(w.member(offset, memberPath), name)
+					}
+				case !exact:
+					if w.caseMismatch != nil {
+						w.caseMismatch(w.member(offset, memberPath), field.Name)
+					}
+				}
+				if field != nil {
+					memberType = field.Type
+					present[field.Name] = true
+				}
+			}
+			err = w.walk(memberType, memberPath)
+			if err != nil {
+				return err
+			}
+		}
+		if w.absent != nil {
+			for _, field := range fields {
+				if !present[field.Name] {
+					w.absent(w.member(start, joinJSONPath(path, field.Name)), field)
+				}
+			}
+		}
+		_, err = w.dec.Token() // '}'
+		return err
+
+	case json.Delim('['):
+		var elem reflect.Type
+		if t != nil && (t.Kind() == reflect.Slice || t.Kind() == reflect.Array) {
+			elem = t.Elem()
+		}
+		for i := 0; w.dec.More(); i++ {
+			err = w.walk(elem, fmt.Sprintf("%s[%d]", path, i))
+			if err != nil {
+				return err
+			}
+		}
+		_, err = w.dec.Token() // ']'
+		return err
+	}
+	// Scalar value was completely read by dec.Token()
+	return nil
+}
+
+// inspectableJSONType returns the non-pointer type of t
+// or nil if the JSON member names of t can't be checked
+// because it implements its own unmarshalling.
+func inspectableJSONType(t reflect.Type) reflect.Type {
+	for t != nil && t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t == nil || t.Kind() == reflect.Interface {
+		return nil
+	}
+	pt := reflect.PointerTo(t)
+	if pt.Implements(jsonUnmarshalerType) || pt.Implements(textUnmarshalerType) {
+		return nil
+	}
+	return t
+}
+
+// jsonStructField is a struct field as seen by encoding/json
+type jsonStructField struct {
+	// Name is the JSON member name of the field
+	Name string
+	// Type is the Go type of the field
+	Type reflect.Type
+	// Optional is true if the field can be left out of the JSON
+	// because its zero value is a valid null or empty value
+	Optional bool
+}
+
+// jsonStructFields returns the JSON fields of the exported fields
+// of the struct type t including the promoted fields of embedded structs.
+func jsonStructFields(t reflect.Type) []jsonStructField {
+	var (
+		fields   []jsonStructField
+		embedded []reflect.Type
+	)
+	for i := range t.NumField() {
+		field := t.Field(i)
+		tag := field.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, opts, _ := strings.Cut(tag, ",")
+		if field.Anonymous && name == "" {
+			ft := field.Type
+			if ft.Kind() == reflect.Pointer {
+				ft = ft.Elem()
+			}
+			if ft.Kind() == reflect.Struct {
+				embedded = append(embedded, ft)
+				continue
+			}
+		}
+		if !field.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+		fields = append(fields, jsonStructField{
+			Name:     name,
+			Type:     field.Type,
+			Optional: isOptionalJSONField(field.Type, opts),
+		})
+	}
+	// Fields of the outer struct take precedence over promoted fields
+	for _, et := range embedded {
+		for _, promoted := range jsonStructFields(et) {
+			if !containsJSONStructField(fields, promoted.Name) {
+				fields = append(fields, promoted)
+			}
+		}
+	}
+	return fields
+}
+
+// isOptionalJSONField returns true if a field of type t
+// with the JSON tag options opts can be left out of the JSON.
+// Pointers, slices, maps, bools, the types of the package
+// github.com/domonda/go-types/nullable and all other
+// types with a "Nullable" name prefix are optional.
+func isOptionalJSONField(t reflect.Type, opts string) bool {
+	for opt := range strings.SplitSeq(opts, ",") {
+		if opt == "omitempty" || opt == "omitzero" {
+			return true
+		}
+	}
+	switch t.Kind() {
+	case reflect.Pointer, reflect.Slice, reflect.Map, reflect.Interface, reflect.Bool:
+		return true
+	}
+	return strings.HasSuffix(t.PkgPath(), "/nullable") || strings.HasPrefix(t.Name(), "Nullable")
+}
+
+func containsJSONStructField(fields []jsonStructField, name string) bool {
+	for i := range fields {
+		if fields[i].Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// matchJSONStructField returns the field matching name
+// like encoding/json does: an exact match is preferred
+// over a case-insensitive match.
+// Returns nil if no field matches.
+func matchJSONStructField(fields []jsonStructField, name string) (field *jsonStructField, exact bool) {
+	for i := range fields {
+		if fields[i].Name == name {
+			return &fields[i], true
+		}
+	}
+	for i := range fields {
+		if strings.EqualFold(fields[i].Name, name) {
+			return &fields[i], false
+		}
+	}
+	return nil, false
+}
+
+func joinJSONPath(path, name string) string {
+	if path == "" {
+		return name
+	}
+	return path + "." + name
+}
+
+// skipJSONSeparators returns the offset of the first byte in data
+// at or after offset that is not whitespace, a comma, or a colon.
+func skipJSONSeparators(data []byte, offset int64) int64 {
+	for offset < int64(len(data)) {
+		switch data[offset] {
+		case ' ', '\t', '\r', '\n', ',', ':':
+			offset++
+		default:
+			return offset
+		}
+	}
+	return offset
+}
+
+// lineColumn returns the 1-based line and column of the byte offset in data.
+func lineColumn(data []byte, offset int64) (line, col int) {
+	offset = min(max(offset, 0), int64(len(data)))
+	before := data[:offset]
+	line = bytes.Count(before, []byte{'\n'}) + 1
+	col = int(offset) - (bytes.LastIndexByte(before, '\n') + 1) + 1
+	return line, col
+}
+
+// jsonErrorWithPosition prefixes syntax and type errors
+// from decoding data with the line and column where they occurred.
+func jsonErrorWithPosition(data []byte, err error) error {
+	var (
+		syntaxErr *json.SyntaxError
+		typeErr   *json.UnmarshalTypeError
+		offset    int64
+	)
+	switch {
+	case errors.As(err, &syntaxErr):
+		offset = syntaxErr.Offset
+	case errors.As(err, &typeErr):
+		offset = typeErr.Offset
+	case errors.Is(err, io.ErrUnexpectedEOF) || errors.Is(err, io.EOF):
+		offset = int64(len(data))
+	default:
+		return err
+	}
+	line, col := lineColumn(data, offset)
+	return fmt.Errorf("line %d column %d: %w", line, col, err)
+}
@@ -0,0 +1,210 @@
+package domonda
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// GLAccountColumns are the default column names per GLAccount field
+// used by ReadGLAccountsCSV and ReadGLAccountsXLSX in addition to the field names themselves.
+var GLAccountColumns = map[string][]string{
+	"Number":   {"Konto", "Kontonummer", "Konto-Nr", "Kontonr", "Sachkonto", "Account", "Account Number", "Account No"},
+	"Name":     {"Bezeichnung", "Kontobezeichnung", "Kontenbezeichnung", "Beschriftung", "Kontenbeschriftung", "Account Name", "Description"},
+	"Category": {"Kategorie", "Kontoklasse", "Kontenklasse", "Kontengruppe", "Gruppe", "Class", "Group"},
+	"ObjectNo": {"Objekt", "Objektnummer", "Objekt-Nr", "Object", "Object Number", "Object No"},
+}
+
+// BankAccountColumns are the default column names per BankAccount field
+// used by ReadBankAccountsCSV and ReadBankAccountsXLSX in addition to the field names themselves.
+var BankAccountColumns = map[string][]string{
+	"BIC":           {"SWIFT", "SWIFT-Code", "BIC/SWIFT"},
+	"Currency":      {"Währung", "Währungscode", "WKZ", "Currency Code"},
+	"Holder":        {"Kontoinhaber", "Inhaber", "Account Holder", "Owner"},
+	"AccountNumber": {"Kontonummer", "Konto-Nr", "Account Number", "Account No"},
+	"Name":          {"Bezeichnung", "Kontobezeichnung", "Account Name"},
+	"Description":   {"Beschreibung", "Anmerkung", "Notiz", "Comment"},
+}
+
+// RealEstateObjectColumns are the default column names per RealEstateObject field
+// used by ReadRealEstateObjectsCSV and ReadRealEstateObjectsXLSX in addition to the field names themselves.
+var RealEstateObjectColumns = map[string][]string{
+	"Type":                 {"Typ", "Objekttyp", "Objektart", "Art", "Object Type"},
+	"Number":               {"Objektnummer", "Objekt-Nr", "Objekt", "Nummer", "Nr", "Object Number", "Object No"},
+	"AccountingArea":       {"Buchungskreis", "Buchhaltungskreis", "Rechnungskreis", "Accounting Area"},
+	"UserAccount":          {"Benutzerkonto", "User Account"},
+	"Description":          {"Bezeichnung", "Beschreibung", "Objektbezeichnung", "Name"},
+	"StreetAddress":        {"Straße", "Strasse", "Adresse", "Address", "Street"},
+	"AlternativeAddresses": {"Weitere Adressen", "Alternative Adressen", "Alternative Addresses"},
+	"ZipCode":              {"PLZ", "Postleitzahl", "ZIP", "Postal Code"},
+	"City":                 {"Ort", "Stadt"},
+	"Country":              {"Land", "Ländercode", "Country Code"},
+	"Active":               {"Aktiv", "Status"},
+}
+
+// ReadGLAccountsCSV reads GL accounts from CSV data with a header line.
+// The columns are mapped to GLAccount fields using config.Columns,
+// config.Profile, and GLAccountColumns.
+// Every account is validated with GLAccount.Validate and rows with errors
+// are returned as RowError with their line number instead of as account.
+// The config can be nil for the defaults.
+func ReadGLAccountsCSV(r io.Reader, config *CSVConfig) (accounts []*GLAccount, rowErrs []*RowError, err error) {
+	return readCSV(r, config, GLAccountColumns, (*GLAccount).Validate)
+}
+
+// ReadGLAccountsXLSX reads GL accounts from a worksheet of XLSX data
+// with a header row like ReadGLAccountsCSV.
+// The config can be nil for the defaults.
+func ReadGLAccountsXLSX(r io.Reader, config *XLSXConfig) (accounts []*GLAccount, rowErrs []*RowError, err error) {
+	return readXLSX(r, config, GLAccountColumns, (*GLAccount).Validate)
+}
+
+// ReadBankAccountsCSV reads bank accounts from CSV data with a header line.
+// The columns are mapped to BankAccount fields using config.Columns,
+// config.Profile, and BankAccountColumns.
+// Every account is normalized with BankAccount.Normalize and validated
+// with BankAccount.Validate and rows with errors are returned as RowError
+// with their line number instead of as account.
+// The config can be nil for the defaults.
+func ReadBankAccountsCSV(r io.Reader, config *CSVConfig) (accounts []*BankAccount, rowErrs []*RowError, err error) {
+	return readCSV(r, config, BankAccountColumns, finalizeBankAccount)
+}
+
+// ReadBankAccountsXLSX reads bank accounts from a worksheet of XLSX data
+// with a header row like ReadBankAccountsCSV.
+// The config can be nil for the defaults.
+func ReadBankAccountsXLSX(r io.Reader, config *XLSXConfig) (accounts []*BankAccount, rowErrs []*RowError, err error) {
+	return readXLSX(r, config, BankAccountColumns, finalizeBankAccount)
+}
+
+func finalizeBankAccount(a *BankAccount) error {
+	if err := a.Normalize(); err != nil {
+		return err
+	}
+	return a.Validate()
+}
+
+// ReadRealEstateObjectsCSV reads real estate objects from CSV data with a header line.
+// The columns are mapped to RealEstateObject fields using config.Columns,
+// config.Profile, and RealEstateObjectColumns.
+// Multiple AlternativeAddresses in one column are separated by "|" or newlines.
+// Every object is normalized with RealEstateObject.Normalize,
+// whose warnings (see IsWarning) are discarded because the
+// warned values are kept unchanged, and validated with RealEstateObject.Validate.
+// Rows with errors are returned as RowError with their line number instead of as object.
+// The config can be nil for the defaults.
+func ReadRealEstateObjectsCSV(r io.Reader, config *CSVConfig) (objects []*RealEstateObject, rowErrs []*RowError, err error) {
+	return readCSV(r, config, RealEstateObjectColumns, finalizeRealEstateObject)
+}
+
+// ReadRealEstateObjectsXLSX reads real estate objects from a worksheet of XLSX data
+// with a header row like ReadRealEstateObjectsCSV.
+// The config can be nil for the defaults.
+func ReadRealEstateObjectsXLSX(r io.Reader, config *XLSXConfig) (objects []*RealEstateObject, rowErrs []*RowError, err error) {
+	return readXLSX(r, config, RealEstateObjectColumns, finalizeRealEstateObject)
+}
+
+func finalizeRealEstateObject(o *RealEstateObject) error {
+	o.Type = RealEstateObjectType(strings.ToUpper(string(o.Type)))
+	if err := errors.Join(withoutWarnings(o.Normalize())...); err != nil {
+		return err
+	}
+	return o.Validate()
+}
+
+// DryRunReport summarizes the items read from a CSV or XLSX file
+// that would be posted by an import, to be checked before posting.
+type DryRunReport struct {
+	// Title of the report like "GL accounts from kontenplan.csv"
+	Title string
+
+	// Valid is the number of valid items that would be posted
+	Valid int
+
+	// RowErrors are the rows that would not be posted
+	RowErrors []*RowError
+
+	// Duplicates are the keys like the account number
+	// of the valid items that occur more than once
+	Duplicates []string
+}
+
+// NewDryRunReport returns a DryRunReport for the items and row errors
+// returned by the Read*CSV and Read*XLSX functions.
+// Duplicates are detected by the Name of partners, the Number and ObjectNo
+// of GL accounts, the Number of real estate objects, and the IBAN of bank accounts.
+func NewDryRunReport[T any](title string, items []*T, rowErrs []*RowError) *DryRunReport {
+	report := &DryRunReport{
+		Title:     title,
+		Valid:     len(items),
+		RowErrors: rowErrs,
+	}
+	counts := make(map[string]int)
+	for _, item := range items {
+		key := dryRunKey(item)
+		if key == "" {
+			continue
+		}
+		counts[key]++
+		if counts[key] == 2 {
+			report.Duplicates = append(report.Duplicates, key)
+		}
+	}
+	return report
+}
+
+func dryRunKey(item any) string {
+	switch item := item.(type) {
+	case *Partner:
+		return strings.ToLower(string(item.Name))
+	case *GLAccount:
+		if item.ObjectNo.IsNotNull() {
+			// The same account number exists for every real estate object
+			return fmt.Sprintf("%s object %s", item.Number, item.ObjectNo.Get())
+		}
+		return string(item.Number)
+	case *BankAccount:
+		return string(item.IBAN)
+	case *RealEstateObject:
+		return string(item.Number)
+	}
+	return ""
+}
+
+// HasErrors returns true if any row had an error
+// or any item was a duplicate.
+func (r *DryRunReport) HasErrors() bool {
+	return len(r.RowErrors) > 0 || len(r.Duplicates) > 0
+}
+
+// String returns the report as plain text
+func (r *DryRunReport) String() string {
+	var b strings.Builder
+	_ = r.WriteText(&b)
+	return b.String()
+}
+
+// WriteText writes the report as plain text to w
+func (r *DryRunReport) WriteText(w io.Writer) error {
+	var b strings.Builder
+	if r.Title != "" {
+		fmt.Fprintf(&b, "%s\n", r.Title)
+	}
+	fmt.Fprintf(&b, "Valid: %d\n", r.Valid)
+	fmt.Fprintf(&b, "Invalid: %d\n", len(r.RowErrors))
+	if len(r.RowErrors) > 0 {
+		b.WriteString("Errors:\n")
+		for _, rowErr := range r.RowErrors {
+			fmt.Fprintf(&b, "  %s\n", strings.ReplaceAll(rowErr.Error(), "\n", "\n    "))
+		}
+	}
+	if len(r.Duplicates) > 0 {
+		b.WriteString("Duplicates:\n")
+		for _, key := range r.Duplicates {
+			fmt.Fprintf(&b, "  %s\n", key)
+		}
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
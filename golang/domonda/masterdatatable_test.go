@@ -0,0 +1,19 @@
+package domonda
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestNewDryRunReportGLAccountObjects(t *testing.T) {
+	accounts := []*GLAccount{
+		{Number: "4000", ObjectNo: "1"},
+		{Number: "4000", ObjectNo: "2"},
+		{Number: "5000"},
+		{Number: "5000"},
+	}
+	report := NewDryRunReport("GL accounts", accounts, nil)
+	if !slices.Equal(report.Duplicates, []string{"5000"}) {
+		t.Errorf("got duplicates %q, expected only 5000", report.Duplicates)
+	}
+}
@@ -0,0 +1,233 @@
+package domonda
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/domonda/go-types/country"
+)
+
+// CallingCodes are the international telephone calling codes of countries
+// used by NormalizePhone to format national numbers.
+// Entries can be added for other countries.
+var CallingCodes = map[country.Code]string{
+	country.AT: "43",
+	country.BE: "32",
+	country.BG: "359",
+	country.CH: "41",
+	country.CY: "357",
+	country.CZ: "420",
+	country.DE: "49",
+	country.DK: "45",
+	country.EE: "372",
+	country.ES: "34",
+	country.FI: "358",
+	country.FR: "33",
+	country.GB: "44",
+	country.GR: "30",
+	country.HR: "385",
+	country.HU: "36",
+	country.IE: "353",
+	country.IT: "39",
+	country.LI: "423",
+	country.LT: "370",
+	country.LU: "352",
+	country.LV: "371",
+	country.MT: "356",
+	country.NL: "31",
+	country.PL: "48",
+	country.PT: "351",
+	country.RO: "40",
+	country.SE: "46",
+	country.SI: "386",
+	country.SK: "421",
+	country.US: "1",
+}
+
+// closedNumberingPlans are the countries without trunk prefix
+// where national numbers don't begin with "0"
+var closedNumberingPlans = map[country.Code]bool{
+	country.CY: true,
+	country.EE: true,
+	country.GR: true,
+	country.LV: true,
+	country.MT: true,
+}
+
+// NormalizePhone returns phone formatted as E.164 number like "+43123456789".
+//
+// International numbers beginning with "+" or "00" are kept,
+// national numbers beginning with the trunk prefix "0" are prefixed with
+// the calling code of defaultCountry from CallingCodes.
+// For countries without trunk prefix like Greece, national numbers
+// not beginning with "0" are prefixed with the calling code.
+// A trunk prefix written as "(0)" after the calling code is removed.
+// Spaces, dashes, slashes, dots, and brackets are removed.
+func NormalizePhone(phone string, defaultCountry country.Code) (string, error) {
+	s := strings.ReplaceAll(strings.TrimSpace(phone), "(0)", "")
+	var digits strings.Builder
+	for i, r := range s {
+		switch {
+		case r >= '0' && r <= '9':
+			digits.WriteRune(r)
+		case r == '+' && i == 0:
+			digits.WriteString("00")
+		case strings.ContainsRune(" -/.()", r):
+		default:
+			return "", fmt.Errorf("Phone '%s' has invalid character %q", phone, r)
+		}
+	}
+	number := digits.String()
+	switch {
+	case strings.HasPrefix(number, "00"):
+		number = number[2:]
+	case strings.HasPrefix(number, "0"):
+		code, ok := CallingCodes[defaultCountry]
+		if !ok {
+			return "", fmt.Errorf("Phone '%s' is a national number without known country calling code", phone)
+		}
+		if defaultCountry != country.IT {
+			// Italian numbers keep the leading zero
+			number = number[1:]
+		}
+		number = code + number
+	case closedNumberingPlans[defaultCountry] && number != "":
+		number = CallingCodes[defaultCountry] + number
+	default:
+		return "", fmt.Errorf("Phone '%s' has no international or national prefix", phone)
+	}
+	if len(number) < 7 || len(number) > 15 {
+		return "", fmt.Errorf("Phone '%s' has invalid length", phone)
+	}
+	return "+" + number, nil
+}
+
+// NormalizeWebsite returns website as URL with lower case host
+// like "https://example.com".
+// The scheme "https://" is added if website has no scheme,
+// "http://" is upgraded to "https://" so that the same website
+// is always written the same way, and a path consisting only of "/" is removed.
+func NormalizeWebsite(website string) (string, error) {
+	s := strings.TrimSpace(website)
+	if !strings.Contains(s, "://") {
+		s = "https://" + s
+	}
+	u, err := url.Parse(s)
+	if err != nil {
+		return "", fmt.Errorf("Website '%s' has error: %w", website, err)
+	}
+	u.Scheme = strings.ToLower(u.Scheme)
+	u.Host = strings.ToLower(u.Host)
+	switch u.Scheme {
+	case "https":
+	case "http":
+		u.Scheme = "https"
+	default:
+		return "", fmt.Errorf("Website '%s' has invalid scheme %q", website, u.Scheme)
+	}
+	if u.User != nil {
+		// Like an email address "name@example.com"
+		return "", fmt.Errorf("Website '%s' has user info", website)
+	}
+	if !strings.Contains(u.Host, ".") || strings.ContainsAny(u.Host, " @") {
+		return "", fmt.Errorf("Website '%s' has invalid host %q", website, u.Host)
+	}
+	if u.Path == "/" && u.RawQuery == "" && u.Fragment == "" {
+		u.Path = ""
+	}
+	return u.String(), nil
+}
+
+var zipCountryPrefix = regexp.MustCompile(`^[A-Z]{1,3}-\s*`)
+
+// NormalizeZIP returns zip in upper case and without
+// country prefix like "A-" or "D-" and checks that it matches
+// the format of the country from ZIPFormats.
+// Countries without format are only trimmed and converted to upper case.
+func NormalizeZIP(zip string, countryCode country.Code) (string, error) {
+	zip = strings.ToUpper(strings.TrimSpace(zip))
+	format, ok := ZIPFormats[countryCode]
+	if !ok || format.MatchString(zip) {
+		return zip, nil
+	}
+	if unprefixed := zipCountryPrefix.ReplaceAllString(zip, ""); format.MatchString(unprefixed) {
+		return unprefixed, nil
+	}
+	return "", fmt.Errorf("ZIP '%s' is not a valid postal code for Country '%s'", zip, countryCode)
+}
+
+var streetNumber = regexp.MustCompile(`^(.*[\p{L}.])\s*(\d.*)$`)
+
+// SplitStreet splits street into the street name and the house number
+// which begins with the last digit that follows a letter or dot
+// like "Hauptstr.12/3" into "Hauptstr." and "12/3".
+// An empty number is returned if street has no house number.
+func SplitStreet(street string) (name, number string) {
+	street = strings.Join(strings.Fields(street), " ")
+	m := streetNumber.FindStringSubmatch(street)
+	if m == nil {
+		return street, ""
+	}
+	return strings.TrimSpace(m[1]), strings.TrimSpace(m[2])
+}
+
+var streetAbbreviation = regexp.MustCompile(`^(\p{L}*-?)(?i)(str\.|str|straße|strasse)$`)
+
+// NormalizeStreet returns street with whitespace collapsed,
+// a single space between street name and house number (see SplitStreet),
+// and abbreviations like "Hauptstr." or "Haupt-Str." written out
+// as "Hauptstraße" or "Haupt-Straße".
+// Only whole words, words following a hyphen, or words
+// with a street name stem of at least 3 letters are written out.
+// The case of the word is kept, so "HAUPTSTR." becomes "HAUPTSTRASSE".
+// For Switzerland and Liechtenstein "strasse" is used instead of "straße".
+func NormalizeStreet(street string, countryCode country.Code) string {
+	strasse := "straße"
+	if countryCode == country.CH || countryCode == country.LI {
+		strasse = "strasse"
+	}
+	name, number := SplitStreet(street)
+	words := strings.Fields(name)
+	for i, word := range words {
+		m := streetAbbreviation.FindStringSubmatch(word)
+		if m == nil {
+			continue
+		}
+		stem, abbr := m[1], m[2]
+		if stem != "" && !strings.HasSuffix(stem, "-") && utf8.RuneCountInString(stem) < 3 {
+			continue
+		}
+		switch {
+		case strings.ToUpper(word) == word:
+			words[i] = stem + "STRASSE"
+		case unicode.IsUpper(rune(abbr[0])):
+			words[i] = stem + "S" + strasse[1:]
+		default:
+			words[i] = stem + strasse
+		}
+	}
+	name = strings.Join(words, " ")
+	if number == "" {
+		return name
+	}
+	return name + " " + number
+}
+
+var zipCity = regexp.MustCompile(`^([A-Z]{1,3}-)?(\d{4,5})\s+(\D.*)$`)
+
+// SplitZIPCity splits a city with a leading postal code like "1010 Wien"
+// or "D-80331 München" into the postal code and the city name.
+// An empty zip and the trimmed city are returned
+// if city doesn't begin with a postal code.
+func SplitZIPCity(city string) (zip, name string) {
+	city = strings.TrimSpace(city)
+	m := zipCity.FindStringSubmatch(city)
+	if m == nil {
+		return "", city
+	}
+	return m[2], strings.TrimSpace(m[3])
+}
@@ -0,0 +1,60 @@
+package domonda
+
+import (
+	"testing"
+
+	"github.com/domonda/go-types/country"
+)
+
+func TestNormalizePhone(t *testing.T) {
+	for _, tt := range []struct {
+		phone   string
+		country country.Code
+		want    string
+		wantErr bool
+	}{
+		{"+43 1 234 56 78", "", "+4312345678", false},
+		{"0043 (0)1 234 56 78", country.DE, "+4312345678", false},
+		{"01/234 56-78", country.AT, "+4312345678", false},
+		{"06 12 34 56 78", country.IT, "+390612345678", false},
+		{"030 1234567", country.DE, "+49301234567", false},
+		{"09 1234 5678", country.FI, "+358912345678", false},
+		{"021 234 5678", country.RO, "+40212345678", false},
+		{"01 234 5678", country.IE, "+35312345678", false},
+		{"21 234 5678", country.PT, "", true},
+		// Countries without trunk prefix
+		{"210 1234567", country.GR, "+302101234567", false},
+		{"2123 4567", country.MT, "+35621234567", false},
+		{"6712 3456", country.LV, "+37167123456", false},
+		{"0123 4567", "", "", true},
+		{"1234567", country.AT, "", true},
+		{"+43 1 234 x", country.AT, "", true},
+	} {
+		got, err := NormalizePhone(tt.phone, tt.country)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("NormalizePhone(%q, %q) = %q, %v, expected %q, error: %t", tt.phone, tt.country, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
+
+func TestNormalizeWebsite(t *testing.T) {
+	for _, tt := range []struct {
+		website string
+		want    string
+		wantErr bool
+	}{
+		{"example.com", "https://example.com", false},
+		{"WWW.Example.com/", "https://www.example.com", false},
+		{"http://example.com", "https://example.com", false},
+		{"HTTP://Example.com/Path?q=1", "https://example.com/Path?q=1", false},
+		{"https://example.com/", "https://example.com", false},
+		{"ftp://example.com", "", true},
+		{"localhost", "", true},
+		{"name@example.com", "", true},
+	} {
+		got, err := NormalizeWebsite(tt.website)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("NormalizeWebsite(%q) = %q, %v, expected %q, error: %t", tt.website, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
@@ -3,30 +3,72 @@ package domonda
 //go:generate go tool go-enum $GOFILE
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
 	"net/url"
 	"regexp"
+
+	"github.com/domonda/go-types/uu"
 )
 
+// ImportObjectInstanceResult contains the result
+// of importing a single instance of a custom object class.
+type ImportObjectInstanceResult struct {
+	// IDPropValue is the value of the ID prop of the imported object
+	IDPropValue any `jsonschema:"required"`
+
+	// ID of the object instance that was created or updated
+	ID uu.NullableID `json:",omitzero"`
+
+	// State of the object instance after import
+	State ImportState `jsonschema:"required"`
+
+	// Error message from the import in case of State "ERROR"
+	Error string `json:",omitempty"`
+}
+
+// ImportState implements the ImportResult interface
+func (r ImportObjectInstanceResult) ImportState() ImportState {
+	return r.State
+}
+
+// ImportError implements the ImportResult interface
+func (r ImportObjectInstanceResult) ImportError() string {
+	return r.Error
+}
+
+// ImportItem implements the ImportResult interface
+func (r ImportObjectInstanceResult) ImportItem() string {
+	if r.IDPropValue == nil {
+		return ""
+	}
+	return fmt.Sprint(r.IDPropValue)
+}
+
 // PostObjectInstancesWithIDProp updates or inserts instances of the class "className"
 // using the prop idPropName as the identifier for the objects.
 // The objectsProps is a slice of maps, where each map represents the properties of an object.
 // The ID prop with idPropName must be present in each object.
 // The source argument is used to identify the source of the request.
-func PostObjectInstancesWithIDProp(ctx context.Context, apiKey string, className, idPropName string, objectsProps []map[string]any, source string) (err error) {
+//
+// Returns a slice of ImportObjectInstanceResult with one result per object
+// or nil if the server responded with an empty body.
+func PostObjectInstancesWithIDProp(ctx context.Context, apiKey string, className, idPropName string, objectsProps []map[string]any, source string) (results []*ImportObjectInstanceResult, err error) {
 	if className == "" {
-		return errors.New("className is required")
+		return nil, errors.New("className is required")
 	}
 	if idPropName == "" {
-		return errors.New("idPropName is required")
+		return nil, errors.New("idPropName is required")
 	}
 	if !validIdentifier.MatchString(className) {
-		return errors.New("className contains invalid characters")
+		return nil, errors.New("className contains invalid characters")
 	}
 	if !validIdentifier.MatchString(idPropName) {
-		return errors.New("idPropName contains invalid characters")
+		return nil, errors.New("idPropName contains invalid characters")
 	}
 
 	for i, props := range objectsProps {
@@ -36,7 +78,7 @@ func PostObjectInstancesWithIDProp(ctx context.Context, apiKey string, className
 	}
 
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	vals := make(url.Values)
@@ -47,12 +89,24 @@ func PostObjectInstancesWithIDProp(ctx context.Context, apiKey string, className
 	endpoint := fmt.Sprintf("/masterdata/upsert-objects/%s/id-prop/%s", className, idPropName)
 	response, err := postJSON(ctx, apiKey, endpoint, vals, objectsProps)
 	if err != nil {
-		return err
+		return nil, err
 	}
+	defer response.Body.Close()
 	if response.StatusCode != 200 {
-		return fmt.Errorf("unexpected status code: %d", response.StatusCode)
+		return nil, fmt.Errorf("unexpected status code: %d", response.StatusCode)
+	}
+	data, err := io.ReadAll(response.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response body: %w", err)
+	}
+	if len(bytes.TrimSpace(data)) == 0 {
+		// Successful import without results in the response
+		return nil, nil
+	}
+	if err := json.Unmarshal(data, &results); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal response body: %w", err)
 	}
-	return nil
+	return results, nil
 }
 
 var validIdentifier = regexp.MustCompile(`^[a-zA-Z0-9_-]+$`)
@@ -1,23 +1,27 @@
 package domonda
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
 	"net/url"
 
 	"github.com/domonda/go-types/account"
 	"github.com/domonda/go-types/notnull"
+	"github.com/domonda/go-types/uu"
 )
 
 type ObjectTenantOwner struct {
-	ObjectNo      account.Number
-	TenantOwnerID int64
-	TenantOwnerNo int64
-	UnitNo        int64
-	Unit          notnull.TrimmedString
-	OwnerLinkNo   int64
-	Owner         notnull.TrimmedString
+	ObjectNo      account.Number        `jsonschema:"required"`
+	TenantOwnerID int64                 `jsonschema:"required"`
+	TenantOwnerNo int64                 `jsonschema:"required"`
+	UnitNo        int64                 `jsonschema:"required"`
+	Unit          notnull.TrimmedString `jsonschema:"required"`
+	OwnerLinkNo   int64                 `jsonschema:"required"`
+	Owner         notnull.TrimmedString `jsonschema:"required"`
 }
 
 func (o *ObjectTenantOwner) Validate() error {
@@ -37,15 +41,63 @@ func (o *ObjectTenantOwner) Validate() error {
 	return errors.Join(errs...)
 }
 
-func PostObjectTenantOwners(ctx context.Context, apiKey string, tenantOwners []*ObjectTenantOwner, source string) error {
-	var err error
+// ImportObjectTenantOwnerResult contains the result
+// of importing a single tenant owner of a real estate object.
+type ImportObjectTenantOwnerResult struct {
+	// Number of the real estate object of the tenant owner
+	ObjectNo account.Number `jsonschema:"required"`
+
+	// TenantOwnerID of the imported tenant owner
+	TenantOwnerID int64 `jsonschema:"required"`
+
+	// ID of the tenant owner that was created or updated
+	ID uu.NullableID `json:",omitzero"`
+
+	// State of the tenant owner after import
+	State ImportState `jsonschema:"required"`
+
+	// Error message from the import in case of State "ERROR"
+	Error string `json:",omitempty"`
+}
+
+// ImportState implements the ImportResult interface
+func (r ImportObjectTenantOwnerResult) ImportState() ImportState {
+	return r.State
+}
+
+// ImportError implements the ImportResult interface
+func (r ImportObjectTenantOwnerResult) ImportError() string {
+	return r.Error
+}
+
+// ImportItem implements the ImportResult interface
+func (r ImportObjectTenantOwnerResult) ImportItem() string {
+	return fmt.Sprintf("%s/%d", r.ObjectNo, r.TenantOwnerID)
+}
+
+// PostObjectTenantOwners upserts (inserts or updates)
+// tenant owners of real estate objects via the Domonda API.
+//
+// Arguments:
+//   - ctx:          Context for the HTTP request (for cancellation and timeouts)
+//   - apiKey:       API key (bearer token) for authentication
+//   - tenantOwners: Slice of tenant owners to import
+//   - source:       Optional identifier for the data source (e.g., your company name)
+//
+// Returns a slice of ImportObjectTenantOwnerResult with one result per input tenant owner
+// or an error if validation fails or the API request fails.
+// The results are nil if the server responded with an empty body.
+// The function validates all tenant owners before sending the request.
+//
+// API endpoint: https://domonda.app/api/public/masterdata/real-estate-object-tenant-owners
+func PostObjectTenantOwners(ctx context.Context, apiKey string, tenantOwners []*ObjectTenantOwner, source string) (results []*ImportObjectTenantOwnerResult, err error) {
 	for i, obj := range tenantOwners {
 		if e := obj.Validate(); e != nil {
 			err = errors.Join(err, fmt.Errorf("ObjectTenantOwner at index %d has error: %w", i, e))
 		}
 	}
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	vals := make(url.Values)
@@ -54,10 +106,22 @@ func PostObjectTenantOwners(ctx context.Context, apiKey string, tenantOwners []*
 	}
 	response, err := postJSON(ctx, apiKey, "/masterdata/real-estate-object-tenant-owners", vals, tenantOwners)
 	if err != nil {
-		return err
+		return nil, err
 	}
+	defer response.Body.Close()
 	if response.StatusCode != 200 {
-		return fmt.Errorf("unexpected status code: %d", response.StatusCode)
+		return nil, fmt.Errorf("unexpected status code: %d", response.StatusCode)
+	}
+	data, err := io.ReadAll(response.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response body: %w", err)
+	}
+	if len(bytes.TrimSpace(data)) == 0 {
+		// Successful import without results in the response
+		return nil, nil
+	}
+	if err := json.Unmarshal(data, &results); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal response body: %w", err)
 	}
-	return nil
+	return results, nil
 }
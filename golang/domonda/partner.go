@@ -32,37 +32,68 @@ type ImportPartnerResult struct {
 	// InputWarnings contains warnings from normalizing and validating the input
 	InputWarnings []string
 
-	// PartnerCompany contains the partner company data after import (JSON format)
-	// TODO replace json.RawMessage with struct types
-	PartnerCompany json.RawMessage `json:",omitempty"`
+	// PartnerCompany contains the partner company data after import
+	PartnerCompany *PartnerCompany `json:",omitempty"`
 
-	// PartnerLocations contains the partner location data after import (JSON format)
+	// PartnerLocations contains the partner location data after import.
 	// Main location is always first in the array
-	PartnerLocations json.RawMessage `json:",omitempty"`
+	PartnerLocations []*PartnerLocation `json:",omitempty"`
 
 	// VendorAccount contains the vendor account data if VendorAccountNumber was provided
-	VendorAccount json.RawMessage `json:",omitempty"`
+	VendorAccount *PartnerAccount `json:",omitempty"`
 
 	// ClientAccount contains the client account data if ClientAccountNumber was provided
-	ClientAccount json.RawMessage `json:",omitempty"`
+	ClientAccount *PartnerAccount `json:",omitempty"`
 
 	// PaymentPresets contains the payment preset data for bank accounts
-	PaymentPresets json.RawMessage `json:",omitempty"`
+	PaymentPresets []*PaymentPreset `json:",omitempty"`
 
 	// State indicates the result: UNCHANGED, UPDATED, CREATED, or ERROR
-	State ImportState
+	State ImportState `jsonschema:"required"`
 
 	// Error contains the error message in case of State "ERROR"
 	Error string `json:",omitempty"`
 }
 
+// ImportState implements the ImportResult interface
+func (r ImportPartnerResult) ImportState() ImportState {
+	return r.State
+}
+
+// ImportError implements the ImportResult interface
+func (r ImportPartnerResult) ImportError() string {
+	return r.Error
+}
+
+// ImportItem implements the ImportResult interface
+func (r ImportPartnerResult) ImportItem() string {
+	if r.PartnerCompany != nil {
+		return r.PartnerCompany.Name.String()
+	}
+	if r.NormalizedInput != nil {
+		return r.NormalizedInput.String()
+	}
+	return ""
+}
+
+// MainLocation returns the main location of the imported partner company
+// or nil if the result has no main location.
+func (r *ImportPartnerResult) MainLocation() *PartnerLocation {
+	for _, location := range r.PartnerLocations {
+		if location.Main {
+			return location
+		}
+	}
+	return nil
+}
+
 // Partner represents a business partner (customer or vendor) with contact information,
 // location data, tax identifiers, account numbers, and bank account details.
 // Partners are identified and updated based on VATIDNo, VendorAccountNumber,
 // ClientAccountNumber, or Name.
 type Partner struct {
 	// Name is the primary company name (required)
-	Name notnull.TrimmedString
+	Name notnull.TrimmedString `jsonschema:"required"`
 
 	// AlternativeNames are additional names used for matching when merging partners
 	AlternativeNames notnull.StringArray
@@ -145,122 +176,25 @@ func (p *Partner) NormalizedAlternativeNames() []string {
 
 // Normalize cleans and validates partner data, fixing common formatting issues
 // and ensuring data consistency. It handles IBAN/BIC formatting, country codes,
-// VAT IDs, email addresses, and bank accounts.
+// VAT IDs, email addresses, bank accounts, addresses, phone numbers, and websites.
 //
 // Arguments:
 //   - resetInvalid: If true, invalid fields are set to null instead of returning errors
 //
 // Returns a slice of errors encountered during normalization. If resetInvalid is true,
 // these are warnings; if false, they indicate validation failures.
+// The errors are of type *FieldError with the name of the invalid field.
+// Errors where IsWarning returns true are returned by additional rules
+// like PartnerRulePhone for values that were kept unchanged
+// like a phone number that could not be formatted.
 //
 // The method also consolidates IBAN/BIC into BankAccounts array and removes duplicates.
+//
+// Normalize applies the rules returned by DefaultPartnerRules,
+// use NormalizeWithRules or ApplyRules for additional or custom rules
+// like the address, phone, and website rules of ContactPartnerRules.
 func (p *Partner) Normalize(resetInvalid bool) []error {
-	var errs []error
-	if p.Name.IsEmpty() {
-		errs = append(errs, errors.New("Name is empty"))
-	}
-
-	p.AlternativeNames = p.NormalizedAlternativeNames()
-
-	var err error
-	p.Country, err = p.Country.Normalized()
-	if err != nil {
-		errs = append(errs, fmt.Errorf("Country '%s' has error: %w", p.Country, err))
-		if resetInvalid {
-			p.Country.SetNull()
-		}
-	}
-	p.VATIDNo, err = p.VATIDNo.Normalized()
-	if err != nil {
-		errs = append(errs, fmt.Errorf("VATIDNo '%s' has error: %w", p.VATIDNo, err))
-		if resetInvalid {
-			p.VATIDNo.SetNull()
-		}
-	}
-	// if p.VATIDNo.ValidAndNotNull() && p.Country.ValidAndNotNull() {
-	// 	vatCountry := p.VATIDNo.Get().CountryCode()
-	// 	if vatCountry != vat.MOSSSchemaVATCountryCode && vatCountry != p.Country.Get() {
-	// 		errs = append(errs, fmt.Errorf("Country '%s' is different from VATIDNo '%s' country code", p.Country, p.VATIDNo))
-	// 		if resetInvalid {
-	// 			if p.Street.IsNotNull() && p.ZIP.IsNotNull() && p.City.IsNotNull() {
-	// 				// If there is a complete address, don't set the country to null
-	// 				p.VATIDNo.SetNull()
-	// 			} else {
-	// 				// If there is no address, keep the VAT ID
-	// 				p.Country.SetNull()
-	// 			}
-	// 		}
-	// 	}
-	// }
-	p.Email, err = p.Email.AddressPart()
-	if err == nil {
-		p.Email, err = p.Email.Normalized()
-	}
-	if err != nil {
-		errs = append(errs, fmt.Errorf("Email '%s' has error: %w", p.Email, err))
-		if resetInvalid {
-			p.Email.SetNull()
-		}
-	}
-	if err = p.VendorAccountNumber.Validate(); err != nil {
-		errs = append(errs, fmt.Errorf("VendorAccountNumber '%s' has error: %w", p.VendorAccountNumber, err))
-		if resetInvalid {
-			p.VendorAccountNumber.SetNull()
-		}
-	}
-	if err = p.ClientAccountNumber.Validate(); err != nil {
-		errs = append(errs, fmt.Errorf("ClientAccountNumber '%s' has error: %w", p.ClientAccountNumber, err))
-		if resetInvalid {
-			p.ClientAccountNumber.SetNull()
-		}
-	}
-	p.IBAN, err = bank.NullableIBAN(strings.ToUpper(string(p.IBAN))).Normalized()
-	if err != nil {
-		errs = append(errs, fmt.Errorf("IBAN '%s' has error: %w", p.IBAN, err))
-		if resetInvalid {
-			p.IBAN.SetNull()
-		}
-	}
-	p.BIC, err = p.BIC.Normalized()
-	if err != nil {
-		errs = append(errs, fmt.Errorf("BIC '%s' has error: %w", p.BIC, err))
-		if resetInvalid {
-			p.BIC.SetNull()
-		}
-	}
-	for i := 0; i < len(p.BankAccounts); i++ {
-		if err = p.BankAccounts[i].Normalize(); err != nil {
-			errs = append(errs, fmt.Errorf("BankAccounts[%d] has error: %w", i, err))
-			if resetInvalid {
-				p.BankAccounts = slices.Delete(p.BankAccounts, i, i+1)
-				i--
-			}
-		}
-	}
-	if p.IBAN.IsNotNull() {
-		// Prepend IBAN/BIC as first bank account
-		p.BankAccounts = append(
-			[]bank.Account{{IBAN: p.IBAN.Get(), BIC: p.BIC}},
-			p.BankAccounts...,
-		)
-		// After prepending, set IBAN and BIC to null
-		p.IBAN.SetNull()
-		p.BIC.SetNull()
-	}
-	// Check for duplicate bank accounts
-	for i := 0; i < len(p.BankAccounts); i++ {
-		seenBefore := slices.ContainsFunc(p.BankAccounts[:i], func(b bank.Account) bool {
-			return b.IBAN == p.BankAccounts[i].IBAN
-		})
-		if seenBefore {
-			errs = append(errs, fmt.Errorf("duplicate bank account: %s", p.BankAccounts[i]))
-			if resetInvalid {
-				p.BankAccounts = slices.Delete(p.BankAccounts, i, i+1)
-				i--
-			}
-		}
-	}
-	return errs
+	return p.ApplyRules(resetInvalid, DefaultPartnerRules())
 }
 
 func (p *Partner) String() string {
@@ -0,0 +1,143 @@
+package domonda
+
+//go:generate go tool go-enum $GOFILE
+
+import (
+	"fmt"
+
+	"github.com/domonda/go-types/account"
+	"github.com/domonda/go-types/bank"
+	"github.com/domonda/go-types/country"
+	"github.com/domonda/go-types/email"
+	"github.com/domonda/go-types/notnull"
+	"github.com/domonda/go-types/nullable"
+	"github.com/domonda/go-types/uu"
+	"github.com/domonda/go-types/vat"
+)
+
+// PartnerCompany is a partner company of the client company
+// as created or updated by PostPartners.
+type PartnerCompany struct {
+	// ID of the partner company in domonda
+	ID uu.NullableID
+
+	// Name is the primary company name
+	Name notnull.TrimmedString
+
+	// AlternativeNames are additional names used for matching
+	AlternativeNames notnull.StringArray
+
+	// DerivedName is the name derived from the company
+	// the partner company is linked to, if any
+	DerivedName nullable.TrimmedString
+}
+
+// PartnerLocation is a location of a partner company
+// as created or updated by PostPartners.
+type PartnerLocation struct {
+	// ID of the partner location in domonda
+	ID uu.NullableID
+
+	// PartnerCompanyID is the ID of the partner company of the location
+	PartnerCompanyID uu.NullableID
+
+	// Main is true for the main location of the partner company
+	Main bool
+
+	// Address details
+	Street  nullable.TrimmedString // Street address with house number
+	City    nullable.TrimmedString // City name
+	ZIP     nullable.TrimmedString // Postal/ZIP code
+	Country country.NullableCode   // ISO 3166-1 alpha-2 country code (e.g., "DE", "AT")
+	Phone   nullable.TrimmedString // Phone number
+	Email   email.NullableAddress  // Email address
+	Website nullable.TrimmedString // Website URL
+
+	// Tax and registration identifiers
+	CompRegNo nullable.TrimmedString // Company registration number
+	TaxIDNo   nullable.TrimmedString // Tax identification number
+	VATIDNo   vat.NullableID         // VAT identification number (e.g., "DE123456789")
+}
+
+// PartnerAccount is a vendor or client account of a partner company
+// in the accounting system as created or updated by PostPartners.
+type PartnerAccount struct {
+	// ID of the partner account in domonda
+	ID uu.NullableID
+
+	// PartnerCompanyID is the ID of the partner company of the account
+	PartnerCompanyID uu.NullableID
+
+	// Type of the account: VENDOR or CLIENT
+	Type PartnerAccountType
+
+	// Number of the account in the accounting system
+	Number account.NullableNumber
+}
+
+// PaymentPreset is a bank account used for payments
+// to a partner company as created or updated by PostPartners.
+type PaymentPreset struct {
+	// ID of the payment preset in domonda
+	ID uu.NullableID
+
+	// PartnerCompanyID is the ID of the partner company of the payment preset
+	PartnerCompanyID uu.NullableID
+
+	// IBAN is the International Bank Account Number
+	IBAN bank.NullableIBAN
+
+	// BIC is the optional Bank Identifier Code (SWIFT)
+	BIC bank.NullableBIC
+}
+
+// PartnerAccountType distinguishes vendor and client accounts of partner companies.
+type PartnerAccountType string //#enum
+
+const (
+	// PartnerAccountTypeVendor is a vendor/creditor account
+	PartnerAccountTypeVendor PartnerAccountType = "VENDOR"
+
+	// PartnerAccountTypeClient is a client/debtor account
+	PartnerAccountTypeClient PartnerAccountType = "CLIENT"
+)
+
+// Valid indicates if p is any of the valid values for PartnerAccountType
+func (p PartnerAccountType) Valid() bool {
+	switch p {
+	case
+		PartnerAccountTypeVendor,
+		PartnerAccountTypeClient:
+		return true
+	}
+	return false
+}
+
+// Validate returns an error if p is none of the valid values for PartnerAccountType
+func (p PartnerAccountType) Validate() error {
+	if !p.Valid() {
+		return fmt.Errorf("invalid value %#v for type domonda.PartnerAccountType", p)
+	}
+	return nil
+}
+
+// Enums returns all valid values for PartnerAccountType
+func (PartnerAccountType) Enums() []PartnerAccountType {
+	return []PartnerAccountType{
+		PartnerAccountTypeVendor,
+		PartnerAccountTypeClient,
+	}
+}
+
+// EnumStrings returns all valid values for PartnerAccountType as strings
+func (PartnerAccountType) EnumStrings() []string {
+	return []string{
+		"VENDOR",
+		"CLIENT",
+	}
+}
+
+// String implements the fmt.Stringer interface for PartnerAccountType
+func (p PartnerAccountType) String() string {
+	return string(p)
+}
@@ -0,0 +1,266 @@
+package domonda
+
+import (
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+
+	"github.com/domonda/go-types/bank"
+
+	"github.com/domonda/api/golang/domonda/partnername"
+)
+
+// PartnerMerge describes input partners of DedupePartners
+// that were merged into one partner.
+type PartnerMerge struct {
+	// Partner is the result of the merge
+	Partner *Partner
+
+	// Indices of the merged input partners,
+	// the first one is the partner the others were merged into
+	Indices []int
+
+	// Reasons describe the matching key for every merged partner
+	// after the first one like `VATIDNo "ATU12345678"`
+	Reasons []string
+
+	// Conflicts describe fields with different values
+	// where the value of the first partner was kept
+	Conflicts []string
+}
+
+// PartnerMergeReport is returned by DedupePartners
+// to review the merges before posting the partners.
+type PartnerMergeReport struct {
+	// Input is the number of input partners
+	Input int
+
+	// Output is the number of deduplicated partners
+	Output int
+
+	// Merges are the partners that were merged from multiple input partners
+	Merges []*PartnerMerge
+}
+
+// HasConflicts returns true if any of the merges has conflicts
+func (r *PartnerMergeReport) HasConflicts() bool {
+	for _, merge := range r.Merges {
+		if len(merge.Conflicts) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// String returns the report as plain text
+func (r *PartnerMergeReport) String() string {
+	var b strings.Builder
+	_ = r.WriteText(&b)
+	return b.String()
+}
+
+// WriteText writes the report as plain text to w
+func (r *PartnerMergeReport) WriteText(w io.Writer) error {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Input: %d\n", r.Input)
+	fmt.Fprintf(&b, "Output: %d\n", r.Output)
+	fmt.Fprintf(&b, "Merged: %d\n", r.Input-r.Output)
+	for _, merge := range r.Merges {
+		fmt.Fprintf(&b, "%s\n", merge.Partner)
+		for i, index := range merge.Indices {
+			if i == 0 {
+				fmt.Fprintf(&b, "  [%d] kept\n", index)
+			} else {
+				fmt.Fprintf(&b, "  [%d] merged by %s\n", index, merge.Reasons[i-1])
+			}
+		}
+		for _, conflict := range merge.Conflicts {
+			fmt.Fprintf(&b, "  conflict: %s\n", conflict)
+		}
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// DedupePartners merges duplicate partners within one batch
+// before posting them with PostPartners.
+//
+// Partners are matched like PostPartners matches existing partners
+// by VATIDNo, VendorAccountNumber, ClientAccountNumber, and Name,
+// where names are compared case insensitive and ignoring
+// punctuation and legal forms like "GmbH" or "AG" (see PartnerNameKey)
+// and the AlternativeNames of the partners are also compared.
+// Partners with different non-null VATIDNo, VendorAccountNumber,
+// or ClientAccountNumber are never merged.
+//
+// Duplicates are merged into the first matching partner:
+// null fields are set from the duplicate, names are added
+// to the AlternativeNames, and bank accounts are combined.
+// Different non-null values are reported as conflicts
+// keeping the value of the first partner.
+//
+// The passed partners are not modified,
+// merged partners are returned as new copies.
+func DedupePartners(partners []*Partner) (deduped []*Partner, report *PartnerMergeReport) {
+	report = &PartnerMergeReport{Input: len(partners)}
+	merges := make(map[*Partner]*PartnerMerge)
+	for i, p := range partners {
+		if p == nil {
+			continue
+		}
+		var (
+			target *Partner
+			reason string
+		)
+		for _, existing := range deduped {
+			if reason = partnerMatch(existing, p); reason != "" {
+				target = existing
+				break
+			}
+		}
+		if target == nil {
+			deduped = append(deduped, p)
+			continue
+		}
+		merge := merges[target]
+		if merge == nil {
+			merge = &PartnerMerge{
+				Partner: clonePartner(target),
+				Indices: []int{slices.Index(partners, target)},
+			}
+			deduped[slices.Index(deduped, target)] = merge.Partner
+			merges[merge.Partner] = merge
+			report.Merges = append(report.Merges, merge)
+		}
+		merge.Indices = append(merge.Indices, i)
+		merge.Reasons = append(merge.Reasons, reason)
+		merge.Conflicts = append(merge.Conflicts, mergePartner(merge.Partner, p)...)
+	}
+	report.Output = len(deduped)
+	return deduped, report
+}
+
+// partnerMatch returns the description of the matching key
+// if p is a duplicate of existing or an empty string if not
+func partnerMatch(existing, p *Partner) string {
+	if existing.VATIDNo.IsNotNull() && p.VATIDNo.IsNotNull() && existing.VATIDNo != p.VATIDNo ||
+		existing.VendorAccountNumber.IsNotNull() && p.VendorAccountNumber.IsNotNull() && existing.VendorAccountNumber != p.VendorAccountNumber ||
+		existing.ClientAccountNumber.IsNotNull() && p.ClientAccountNumber.IsNotNull() && existing.ClientAccountNumber != p.ClientAccountNumber {
+		return ""
+	}
+	switch {
+	case p.VATIDNo.IsNotNull() && existing.VATIDNo == p.VATIDNo:
+		return fmt.Sprintf("VATIDNo %q", p.VATIDNo)
+	case p.VendorAccountNumber.IsNotNull() && existing.VendorAccountNumber == p.VendorAccountNumber:
+		return fmt.Sprintf("VendorAccountNumber %q", p.VendorAccountNumber)
+	case p.ClientAccountNumber.IsNotNull() && existing.ClientAccountNumber == p.ClientAccountNumber:
+		return fmt.Sprintf("ClientAccountNumber %q", p.ClientAccountNumber)
+	}
+	existingKeys := partnerNameKeys(existing)
+	for _, key := range partnerNameKeys(p) {
+		if slices.Contains(existingKeys, key) {
+			return fmt.Sprintf("Name %q", key)
+		}
+	}
+	return ""
+}
+
+func partnerNameKeys(p *Partner) []string {
+	keys := make([]string, 0, 1+len(p.AlternativeNames))
+	for _, name := range append([]string{string(p.Name)}, p.AlternativeNames...) {
+		if key := PartnerNameKey(name); key != "" {
+			keys = append(keys, key)
+		}
+	}
+	return keys
+}
+
+// PartnerNameKey returns the name normalized by partnername.Normalize
+// in lower case, without punctuation and umlauts, and with trailing
+// legal forms like "GmbH", "AG", or "GmbH & Co. KG" removed,
+// used to compare partner names.
+func PartnerNameKey(name string) string {
+	return partnername.Normalize(name)
+}
+
+// MatchPartnerName returns the partner with the highest similarity
+// of its Name or AlternativeNames to name as returned by partnername.Similarity
+// together with the similarity, or nil if no partner
+// has at least a similarity of minSimilarity.
+// Use it to find the partner of an Invoice.PartnerName.
+func MatchPartnerName(partners []*Partner, name string, minSimilarity float64) (match *Partner, similarity float64) {
+	for _, p := range partners {
+		if p == nil {
+			continue
+		}
+		names := append([]string{string(p.Name)}, p.AlternativeNames...)
+		if i, s := partnername.BestMatch(name, names, minSimilarity); i >= 0 && s > similarity {
+			match, similarity = p, s
+		}
+	}
+	return match, similarity
+}
+
+func clonePartner(p *Partner) *Partner {
+	c := *p
+	c.AlternativeNames = slices.Clone(p.AlternativeNames)
+	c.BankAccounts = slices.Clone(p.BankAccounts)
+	return &c
+}
+
+// mergePartner merges src into dst and returns the conflicts
+func mergePartner(dst, src *Partner) (conflicts []string) {
+	conflict := func(field, kept, dropped string) {
+		if kept != "" && dropped != "" && kept != dropped {
+			conflicts = append(conflicts, fmt.Sprintf("%s %q kept instead of %q", field, kept, dropped))
+		}
+	}
+	mergeField(&dst.Street, src.Street, "Street", conflict)
+	mergeField(&dst.City, src.City, "City", conflict)
+	mergeField(&dst.ZIP, src.ZIP, "ZIP", conflict)
+	mergeField(&dst.Country, src.Country, "Country", conflict)
+	mergeField(&dst.Phone, src.Phone, "Phone", conflict)
+	mergeField(&dst.Email, src.Email, "Email", conflict)
+	mergeField(&dst.Website, src.Website, "Website", conflict)
+	mergeField(&dst.CompRegNo, src.CompRegNo, "CompRegNo", conflict)
+	mergeField(&dst.TaxIDNo, src.TaxIDNo, "TaxIDNo", conflict)
+	mergeField(&dst.VATIDNo, src.VATIDNo, "VATIDNo", conflict)
+	mergeField(&dst.VendorAccountNumber, src.VendorAccountNumber, "VendorAccountNumber", conflict)
+	mergeField(&dst.ClientAccountNumber, src.ClientAccountNumber, "ClientAccountNumber", conflict)
+
+	for _, name := range append([]string{string(src.Name)}, src.AlternativeNames...) {
+		name = strings.TrimSpace(name)
+		if name == "" || strings.EqualFold(name, string(dst.Name)) {
+			continue
+		}
+		if !slices.ContainsFunc(dst.AlternativeNames, func(n string) bool { return strings.EqualFold(n, name) }) {
+			dst.AlternativeNames = append(dst.AlternativeNames, name)
+		}
+	}
+	slices.Sort(dst.AlternativeNames)
+
+	srcAccounts := src.BankAccounts
+	if src.IBAN.IsNotNull() {
+		srcAccounts = append([]bank.Account{{IBAN: src.IBAN.Get(), BIC: src.BIC}}, srcAccounts...)
+	}
+	for _, acc := range srcAccounts {
+		if dst.IBAN == acc.IBAN.Nullable() {
+			continue
+		}
+		if !slices.ContainsFunc(dst.BankAccounts, func(b bank.Account) bool { return b.IBAN == acc.IBAN }) {
+			dst.BankAccounts = append(dst.BankAccounts, acc)
+		}
+	}
+	return conflicts
+}
+
+// mergeField sets dst to src if dst is null (empty string)
+// and calls conflict for different values
+func mergeField[T ~string](dst *T, src T, field string, conflict func(field, kept, dropped string)) {
+	if *dst == "" {
+		*dst = src
+		return
+	}
+	conflict(field, string(*dst), string(src))
+}
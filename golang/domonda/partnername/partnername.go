@@ -0,0 +1,183 @@
+// Package partnername normalizes and compares company names
+// to match partner names of invoices with partner master data.
+package partnername
+
+import (
+	"slices"
+	"strings"
+	"unicode"
+)
+
+// legalForms are legal forms as normalized tokens.
+// Multi token legal forms like "GmbH & Co. KG" are removed token by token.
+var legalForms = []string{
+	// Austria and Germany
+	"gmbh", "gesmbh", "mbh", "ag", "kg", "og", "ohg", "eu", "ek", "ekfm", "gbr", "ug", "se", "co",
+	"haftungsbeschraenkt", "kgaa", "eg", "ev",
+	// Other countries
+	"ltd", "llc", "inc", "corp", "plc", "sa", "sarl", "srl", "spa", "bv", "nv", "sro", "spzoo",
+}
+
+// folding maps letters to their ASCII replacement
+var folding = map[rune]string{
+	'ä': "ae", 'ö': "oe", 'ü': "ue", 'ß': "ss",
+	'à': "a", 'á': "a", 'â': "a", 'ã': "a", 'å': "a", 'æ': "ae",
+	'ç': "c", 'č': "c", 'ć': "c",
+	'è': "e", 'é': "e", 'ê': "e", 'ë': "e", 'ě': "e",
+	'ì': "i", 'í': "i", 'î': "i", 'ï': "i",
+	'ñ': "n", 'ň': "n",
+	'ò': "o", 'ó': "o", 'ô': "o", 'õ': "o", 'ø': "o", 'œ': "oe",
+	'ř': "r", 'š': "s", 'ś': "s", 'ť': "t",
+	'ù': "u", 'ú': "u", 'û': "u", 'ů': "u",
+	'ý': "y", 'ÿ': "y", 'ž': "z", 'ź': "z", 'ż': "z", 'ł': "l",
+}
+
+// Fold returns s in lower case with umlauts and ß replaced
+// by their two letter spelling like "ae" and "ss"
+// and accents removed from other latin letters.
+func Fold(s string) string {
+	var b strings.Builder
+	b.Grow(len(s))
+	for _, r := range strings.ToLower(s) {
+		if f, ok := folding[r]; ok {
+			b.WriteString(f)
+		} else {
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+// Tokens returns the folded words of name without punctuation.
+// Dots within words are removed so that abbreviations like
+// "e.U." or "Ges.m.b.H." result in single tokens.
+func Tokens(name string) []string {
+	tokens := strings.FieldsFunc(Fold(name), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '.'
+	})
+	for i, token := range tokens {
+		tokens[i] = strings.ReplaceAll(token, ".", "")
+	}
+	return slices.DeleteFunc(tokens, func(token string) bool { return token == "" })
+}
+
+// StripLegalForm returns the tokens of name (see Tokens)
+// with trailing legal forms like "GmbH", "AG", "KG", "e.U.", "OG",
+// or "GmbH & Co. KG" removed.
+// The first token is never removed so that names
+// consisting only of a legal form are kept.
+func StripLegalForm(name string) []string {
+	tokens := Tokens(name)
+	for len(tokens) > 1 && IsLegalForm(tokens[len(tokens)-1]) {
+		tokens = tokens[:len(tokens)-1]
+	}
+	return tokens
+}
+
+// IsLegalForm returns true if token is a legal form like "GmbH" or "e.U."
+func IsLegalForm(token string) bool {
+	return slices.Contains(legalForms, strings.Join(Tokens(token), ""))
+}
+
+// Normalize returns the name folded, without punctuation,
+// and without trailing legal forms, with the words joined by single spaces.
+// Names with equal normalized names are considered the same company.
+func Normalize(name string) string {
+	return strings.Join(StripLegalForm(name), " ")
+}
+
+// Equal returns true if the normalized names of a and b are equal
+func Equal(a, b string) bool {
+	return Normalize(a) == Normalize(b)
+}
+
+// Similarity returns the token set similarity of the normalized names
+// from 0 for no similarity to 1 for equal normalized names.
+//
+// The score is the Dice coefficient of the token sets
+// where every token of the shorter name is paired with the most similar
+// remaining token of the other name, so the order of the words doesn't matter
+// and tokens with small typos count as partial matches.
+func Similarity(a, b string) float64 {
+	tokensA, tokensB := uniqueTokens(a), uniqueTokens(b)
+	if len(tokensA) == 0 || len(tokensB) == 0 {
+		return 0
+	}
+	if len(tokensA) > len(tokensB) {
+		tokensA, tokensB = tokensB, tokensA
+	}
+	remaining := slices.Clone(tokensB)
+	var matched float64
+	for _, token := range tokensA {
+		best, bestIndex := 0.0, -1
+		for i, other := range remaining {
+			if s := tokenSimilarity(token, other); s > best {
+				best, bestIndex = s, i
+			}
+		}
+		if bestIndex >= 0 {
+			matched += best
+			remaining = slices.Delete(remaining, bestIndex, bestIndex+1)
+		}
+	}
+	return 2 * matched / float64(len(tokensA)+len(tokensB))
+}
+
+func uniqueTokens(name string) []string {
+	tokens := StripLegalForm(name)
+	slices.Sort(tokens)
+	return slices.Compact(tokens)
+}
+
+// minTokenSimilarity is the minimum similarity of two
+// different tokens to count as partial match
+const minTokenSimilarity = 0.8
+
+// tokenSimilarity returns 1 minus the Levenshtein distance
+// relative to the length of the longer token,
+// or 0 if that is below minTokenSimilarity
+func tokenSimilarity(a, b string) float64 {
+	if a == b {
+		return 1
+	}
+	ra, rb := []rune(a), []rune(b)
+	s := 1 - float64(levenshtein(ra, rb))/float64(max(len(ra), len(rb)))
+	if s < minTokenSimilarity {
+		return 0
+	}
+	return s
+}
+
+func levenshtein(a, b []rune) int {
+	prev := make([]int, len(b)+1)
+	curr := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := range a {
+		curr[0] = i + 1
+		for j := range b {
+			cost := 1
+			if a[i] == b[j] {
+				cost = 0
+			}
+			curr[j+1] = min(prev[j+1]+1, curr[j]+1, prev[j]+cost)
+		}
+		prev, curr = curr, prev
+	}
+	return prev[len(b)]
+}
+
+// BestMatch returns the index of the candidate with the highest
+// Similarity to name and its similarity,
+// or -1 if no candidate has at least minSimilarity.
+// Earlier candidates win on equal similarity.
+func BestMatch(name string, candidates []string, minSimilarity float64) (index int, similarity float64) {
+	index = -1
+	for i, candidate := range candidates {
+		if s := Similarity(name, candidate); s >= minSimilarity && s > similarity {
+			index, similarity = i, s
+		}
+	}
+	return index, similarity
+}
@@ -0,0 +1,454 @@
+package domonda
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/domonda/go-types/bank"
+	"github.com/domonda/go-types/country"
+)
+
+// FieldError is an error or warning of a normalization rule
+// for a field of the normalized struct.
+type FieldError struct {
+	// Field is the name of the struct field like "VATIDNo"
+	Field string
+
+	// Err describes the problem of the field
+	Err error
+
+	// Warning is true if the field was kept because the value
+	// could still be correct, like a phone number that
+	// could not be formatted or a street without house number.
+	// Warnings are never returned for fields set to null.
+	Warning bool
+}
+
+func (e *FieldError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *FieldError) Unwrap() error {
+	return e.Err
+}
+
+// fieldErrorf returns a *FieldError for field with a formatted error
+func fieldErrorf(field, format string, args ...any) *FieldError {
+	return &FieldError{Field: field, Err: fmt.Errorf(format, args...)}
+}
+
+// fieldWarning returns a *FieldError warning for field
+func fieldWarning(field string, err error) *FieldError {
+	return &FieldError{Field: field, Err: err, Warning: true}
+}
+
+// IsWarning returns true if err is a *FieldError with Warning set.
+func IsWarning(err error) bool {
+	var fieldErr *FieldError
+	return errors.As(err, &fieldErr) && fieldErr.Warning
+}
+
+// withoutWarnings returns errs without the errors where IsWarning is true
+func withoutWarnings(errs []error) []error {
+	var result []error
+	for _, err := range errs {
+		if !IsWarning(err) {
+			result = append(result, err)
+		}
+	}
+	return result
+}
+
+// PartnerRule is a normalization rule for a Partner
+// applied by Partner.ApplyRules.
+//
+// A rule fixes the formatting of fields and returns errors
+// for invalid or inconsistent fields, preferably as *FieldError.
+// If resetInvalid is true, the rule may set invalid fields to null
+// and the returned errors are warnings like for Partner.Normalize.
+type PartnerRule func(p *Partner, resetInvalid bool) []error
+
+// DefaultPartnerRules returns the rules applied by Partner.Normalize in order.
+// The returned slice can be modified to remove, reorder, or add rules
+// and then be passed to Partner.ApplyRules.
+func DefaultPartnerRules() []PartnerRule {
+	return []PartnerRule{
+		PartnerRuleName,
+		PartnerRuleCountry,
+		PartnerRuleVATID,
+		PartnerRuleEmail,
+		PartnerRuleAccountNumbers,
+		PartnerRuleIBAN,
+		PartnerRuleBankAccounts,
+	}
+}
+
+// ContactPartnerRules returns the opt-in rules that normalize
+// the address, phone number, and website of a partner:
+// PartnerRuleStreet, PartnerRuleZIPFormat, PartnerRulePhone, and PartnerRuleWebsite.
+// They are not applied by Partner.Normalize because they change
+// existing values, pass them to Partner.NormalizeWithRules:
+//
+//	errs := partner.NormalizeWithRules(true, domonda.ContactPartnerRules()...)
+func ContactPartnerRules() []PartnerRule {
+	return []PartnerRule{
+		PartnerRuleStreet,
+		PartnerRuleZIPFormat,
+		PartnerRulePhone,
+		PartnerRuleWebsite,
+	}
+}
+
+// ApplyRules applies the rules in order and returns the errors of all rules.
+//
+// Example:
+//
+//	rules := append(domonda.DefaultPartnerRules(), domonda.PartnerRuleVATIDCountry, domonda.PartnerRuleIBANCountry)
+//	errs := partner.ApplyRules(true, rules)
+func (p *Partner) ApplyRules(resetInvalid bool, rules []PartnerRule) []error {
+	var errs []error
+	for _, rule := range rules {
+		errs = append(errs, rule(p, resetInvalid)...)
+	}
+	return errs
+}
+
+// NormalizeWithRules calls Normalize and then applies the rules in order
+// returning the errors of Normalize and all rules.
+//
+// Example:
+//
+//	errs := partner.NormalizeWithRules(true, domonda.PartnerRuleVATIDCountry, domonda.PartnerRuleIBANCountry)
+func (p *Partner) NormalizeWithRules(resetInvalid bool, rules ...PartnerRule) []error {
+	return p.ApplyRules(resetInvalid, append(DefaultPartnerRules(), rules...))
+}
+
+// PartnerRuleName checks that Name is not empty and normalizes
+// the AlternativeNames, see Partner.NormalizedAlternativeNames.
+func PartnerRuleName(p *Partner, resetInvalid bool) []error {
+	var errs []error
+	if p.Name.IsEmpty() {
+		errs = append(errs, &FieldError{Field: "Name", Err: errors.New("Name is empty")})
+	}
+	p.AlternativeNames = p.NormalizedAlternativeNames()
+	return errs
+}
+
+// PartnerRuleCountry normalizes Country.
+func PartnerRuleCountry(p *Partner, resetInvalid bool) []error {
+	var err error
+	p.Country, err = p.Country.Normalized()
+	if err != nil {
+		if resetInvalid {
+			p.Country.SetNull()
+		}
+		return []error{fieldErrorf("Country", "Country '%s' has error: %w", p.Country, err)}
+	}
+	return nil
+}
+
+// PartnerRuleVATID normalizes VATIDNo.
+func PartnerRuleVATID(p *Partner, resetInvalid bool) []error {
+	var err error
+	p.VATIDNo, err = p.VATIDNo.Normalized()
+	if err != nil {
+		e := fieldErrorf("VATIDNo", "VATIDNo '%s' has error: %w", p.VATIDNo, err)
+		if resetInvalid {
+			p.VATIDNo.SetNull()
+		}
+		return []error{e}
+	}
+	return nil
+}
+
+// PartnerRuleEmail extracts the address part of Email
+// like from "Name <name@example.com>" and normalizes it.
+func PartnerRuleEmail(p *Partner, resetInvalid bool) []error {
+	var err error
+	p.Email, err = p.Email.AddressPart()
+	if err == nil {
+		p.Email, err = p.Email.Normalized()
+	}
+	if err != nil {
+		e := fieldErrorf("Email", "Email '%s' has error: %w", p.Email, err)
+		if resetInvalid {
+			p.Email.SetNull()
+		}
+		return []error{e}
+	}
+	return nil
+}
+
+// PartnerRuleAccountNumbers validates VendorAccountNumber and ClientAccountNumber.
+func PartnerRuleAccountNumbers(p *Partner, resetInvalid bool) []error {
+	var errs []error
+	if err := p.VendorAccountNumber.Validate(); err != nil {
+		errs = append(errs, fieldErrorf("VendorAccountNumber", "VendorAccountNumber '%s' has error: %w", p.VendorAccountNumber, err))
+		if resetInvalid {
+			p.VendorAccountNumber.SetNull()
+		}
+	}
+	if err := p.ClientAccountNumber.Validate(); err != nil {
+		errs = append(errs, fieldErrorf("ClientAccountNumber", "ClientAccountNumber '%s' has error: %w", p.ClientAccountNumber, err))
+		if resetInvalid {
+			p.ClientAccountNumber.SetNull()
+		}
+	}
+	return errs
+}
+
+// PartnerRuleIBAN converts IBAN to upper case and normalizes IBAN and BIC.
+func PartnerRuleIBAN(p *Partner, resetInvalid bool) []error {
+	var (
+		errs []error
+		err  error
+	)
+	p.IBAN, err = bank.NullableIBAN(strings.ToUpper(string(p.IBAN))).Normalized()
+	if err != nil {
+		errs = append(errs, fieldErrorf("IBAN", "IBAN '%s' has error: %w", p.IBAN, err))
+		if resetInvalid {
+			p.IBAN.SetNull()
+		}
+	}
+	p.BIC, err = p.BIC.Normalized()
+	if err != nil {
+		errs = append(errs, fieldErrorf("BIC", "BIC '%s' has error: %w", p.BIC, err))
+		if resetInvalid {
+			p.BIC.SetNull()
+		}
+	}
+	return errs
+}
+
+// PartnerRuleBankAccounts normalizes BankAccounts,
+// prepends IBAN and BIC as first bank account setting them to null,
+// and removes duplicate bank accounts.
+func PartnerRuleBankAccounts(p *Partner, resetInvalid bool) []error {
+	var errs []error
+	for i := 0; i < len(p.BankAccounts); i++ {
+		if err := p.BankAccounts[i].Normalize(); err != nil {
+			errs = append(errs, fieldErrorf("BankAccounts", "BankAccounts[%d] has error: %w", i, err))
+			if resetInvalid {
+				p.BankAccounts = slices.Delete(p.BankAccounts, i, i+1)
+				i--
+			}
+		}
+	}
+	if p.IBAN.IsNotNull() {
+		// Prepend IBAN/BIC as first bank account
+		p.BankAccounts = append(
+			[]bank.Account{{IBAN: p.IBAN.Get(), BIC: p.BIC}},
+			p.BankAccounts...,
+		)
+		// After prepending, set IBAN and BIC to null
+		p.IBAN.SetNull()
+		p.BIC.SetNull()
+	}
+	// Check for duplicate bank accounts
+	for i := 0; i < len(p.BankAccounts); i++ {
+		seenBefore := slices.ContainsFunc(p.BankAccounts[:i], func(b bank.Account) bool {
+			return b.IBAN == p.BankAccounts[i].IBAN
+		})
+		if seenBefore {
+			errs = append(errs, fieldErrorf("BankAccounts", "duplicate bank account: %s", p.BankAccounts[i]))
+			if resetInvalid {
+				p.BankAccounts = slices.Delete(p.BankAccounts, i, i+1)
+				i--
+			}
+		}
+	}
+	return errs
+}
+
+// PartnerRuleVATIDCountry checks that the country code of VATIDNo
+// is the same as Country. VAT IDs of the Mini One Stop Shop (MOSS)
+// schema beginning with "EU" are not checked.
+//
+// If resetInvalid is true and the partner has a complete address,
+// VATIDNo is set to null because the address is more likely correct,
+// else Country is set to null and the VAT ID is kept.
+func PartnerRuleVATIDCountry(p *Partner, resetInvalid bool) []error {
+	if !p.VATIDNo.ValidAndNotNull() || !p.Country.ValidAndNotNull() || p.VATIDNo.Get().IsMOSS() {
+		return nil
+	}
+	vatCountry := p.VATIDNo.Get().CountryCode()
+	if vatCountry == "EL" {
+		// Greek VAT IDs use "EL" instead of the ISO code "GR"
+		vatCountry = country.GR
+	}
+	if vatCountry == p.Country.Get() {
+		return nil
+	}
+	err := fieldErrorf("Country", "Country '%s' is different from VATIDNo '%s' country code", p.Country, p.VATIDNo)
+	if resetInvalid {
+		if p.Street.IsNotNull() && p.ZIP.IsNotNull() && p.City.IsNotNull() {
+			// If there is a complete address, don't set the country to null
+			err.Field = "VATIDNo"
+			p.VATIDNo.SetNull()
+		} else {
+			// If there is no address, keep the VAT ID
+			p.Country.SetNull()
+		}
+	}
+	return []error{err}
+}
+
+// PartnerRuleIBANCountry checks that the country codes
+// of IBAN and BankAccounts are the same as Country.
+// Partners may have bank accounts in other countries,
+// so the errors are only warnings and no fields are reset.
+func PartnerRuleIBANCountry(p *Partner, resetInvalid bool) []error {
+	if !p.Country.ValidAndNotNull() {
+		return nil
+	}
+	var errs []error
+	if p.IBAN.ValidAndNotNull() && p.IBAN.Get().CountryCode() != p.Country.Get() {
+		errs = append(errs, fieldWarning("IBAN", fmt.Errorf("Country '%s' is different from IBAN '%s' country code", p.Country, p.IBAN)))
+	}
+	for i, acc := range p.BankAccounts {
+		if acc.IBAN.Valid() && acc.IBAN.CountryCode() != p.Country.Get() {
+			errs = append(errs, fieldWarning("BankAccounts", fmt.Errorf("Country '%s' is different from BankAccounts[%d] IBAN '%s' country code", p.Country, i, acc.IBAN)))
+		}
+	}
+	return errs
+}
+
+// ZIPFormats are regular expressions for the postal codes
+// of countries checked by PartnerRuleZIPFormat.
+// Entries can be added or changed for other countries.
+var ZIPFormats = map[country.Code]*regexp.Regexp{
+	country.AT: regexp.MustCompile(`^\d{4}$`),
+	country.BE: regexp.MustCompile(`^\d{4}$`),
+	country.BG: regexp.MustCompile(`^\d{4}$`),
+	country.CH: regexp.MustCompile(`^\d{4}$`),
+	country.CY: regexp.MustCompile(`^\d{4}$`),
+	country.CZ: regexp.MustCompile(`^\d{3} ?\d{2}$`),
+	country.DE: regexp.MustCompile(`^\d{5}$`),
+	country.DK: regexp.MustCompile(`^\d{4}$`),
+	country.EE: regexp.MustCompile(`^\d{5}$`),
+	country.ES: regexp.MustCompile(`^\d{5}$`),
+	country.FI: regexp.MustCompile(`^\d{5}$`),
+	country.FR: regexp.MustCompile(`^\d{5}$`),
+	country.GB: regexp.MustCompile(`^[A-Z]{1,2}\d[A-Z\d]? ?\d[A-Z]{2}$`),
+	country.GR: regexp.MustCompile(`^\d{3} ?\d{2}$`),
+	country.HR: regexp.MustCompile(`^\d{5}$`),
+	country.HU: regexp.MustCompile(`^\d{4}$`),
+	country.IE: regexp.MustCompile(`^[A-Z]\d[\dW] ?[A-Z\d]{4}$`),
+	country.IT: regexp.MustCompile(`^\d{5}$`),
+	country.LI: regexp.MustCompile(`^\d{4}$`),
+	country.LT: regexp.MustCompile(`^\d{5}$`),
+	country.LU: regexp.MustCompile(`^\d{4}$`),
+	country.LV: regexp.MustCompile(`^\d{4}$`),
+	country.MT: regexp.MustCompile(`^[A-Z]{3} ?\d{4}$`),
+	country.NL: regexp.MustCompile(`^\d{4} ?[A-Z]{2}$`),
+	country.PL: regexp.MustCompile(`^\d{2}-\d{3}$`),
+	country.PT: regexp.MustCompile(`^\d{4}-\d{3}$`),
+	country.RO: regexp.MustCompile(`^\d{6}$`),
+	country.SE: regexp.MustCompile(`^\d{3} ?\d{2}$`),
+	country.SI: regexp.MustCompile(`^\d{4}$`),
+	country.SK: regexp.MustCompile(`^\d{3} ?\d{2}$`),
+	country.US: regexp.MustCompile(`^\d{5}(-\d{4})?$`),
+}
+
+// PartnerRuleZIPFormat normalizes ZIP with NormalizeZIP,
+// also taking the ZIP from the beginning of City like "1010 Wien"
+// if ZIP is null, see SplitZIPCity.
+// A warning is returned if ZIP doesn't match the format of Country from ZIPFormats
+// and ZIP is kept unchanged, countries without format are not checked.
+func PartnerRuleZIPFormat(p *Partner, resetInvalid bool) []error {
+	if p.ZIP.IsNull() && p.City.IsNotNull() {
+		if zip, city := SplitZIPCity(p.City.String()); zip != "" {
+			p.ZIP.Set(zip)
+			p.City.Set(city)
+		}
+	}
+	if p.ZIP.IsNull() || !p.Country.ValidAndNotNull() {
+		return nil
+	}
+	zip, err := NormalizeZIP(p.ZIP.String(), p.Country.Get())
+	if err != nil {
+		return []error{fieldWarning("ZIP", err)}
+	}
+	p.ZIP.Set(zip)
+	return nil
+}
+
+// PartnerRulePhone formats Phone as E.164 number like "+43123456789"
+// using the calling code of Country for national numbers,
+// see NormalizePhone.
+// A warning is returned if Phone can't be formatted and Phone is kept unchanged.
+func PartnerRulePhone(p *Partner, resetInvalid bool) []error {
+	if p.Phone.IsNull() {
+		return nil
+	}
+	phone, err := NormalizePhone(p.Phone.String(), p.Country.GetOr(""))
+	if err != nil {
+		return []error{fieldWarning("Phone", err)}
+	}
+	p.Phone.Set(phone)
+	return nil
+}
+
+// PartnerRuleWebsite normalizes Website to an URL
+// like "https://example.com", see NormalizeWebsite.
+// If Website is invalid, a warning is returned
+// and Website is set to null if resetInvalid is true.
+func PartnerRuleWebsite(p *Partner, resetInvalid bool) []error {
+	if p.Website.IsNull() {
+		return nil
+	}
+	website, err := NormalizeWebsite(p.Website.String())
+	if err != nil {
+		if resetInvalid {
+			p.Website.SetNull()
+			return []error{&FieldError{Field: "Website", Err: err}}
+		}
+		return []error{fieldWarning("Website", err)}
+	}
+	p.Website.Set(website)
+	return nil
+}
+
+// PartnerRuleStreet normalizes Street with NormalizeStreet
+// using Country for the spelling of "Straße".
+// A warning is returned for a Street without house number.
+func PartnerRuleStreet(p *Partner, resetInvalid bool) []error {
+	if p.Street.IsNull() {
+		return nil
+	}
+	p.Street.Set(NormalizeStreet(p.Street.String(), p.Country.GetOr("")))
+	if _, number := SplitStreet(p.Street.String()); number == "" {
+		return []error{fieldWarning("Street", fmt.Errorf("Street '%s' has no house number", p.Street))}
+	}
+	return nil
+}
+
+// PartnerRuleBankDirectory returns a rule that sets missing BICs
+// of IBAN and BankAccounts from the bank directory dir
+// and returns warnings for BICs that are different
+// from the BIC of the directory, see BankDirectory.CheckBIC.
+// Use DefaultBankDirectory or a directory with the official bank lists.
+func PartnerRuleBankDirectory(dir *BankDirectory) PartnerRule {
+	return func(p *Partner, resetInvalid bool) []error {
+		var errs []error
+		if p.IBAN.IsNotNull() {
+			if p.BIC.IsNull() {
+				p.BIC = dir.BICForIBAN(p.IBAN.Get()).Nullable()
+			} else if err := dir.CheckBIC(p.IBAN.Get(), p.BIC.Get()); err != nil {
+				errs = append(errs, fieldWarning("BIC", err))
+			}
+		}
+		for i := range p.BankAccounts {
+			acc := &p.BankAccounts[i]
+			if acc.BIC.IsNull() {
+				acc.BIC = dir.BICForIBAN(acc.IBAN).Nullable()
+			} else if err := dir.CheckBIC(acc.IBAN, acc.BIC.Get()); err != nil {
+				errs = append(errs, fieldWarning("BankAccounts", fmt.Errorf("BankAccounts[%d]: %w", i, err)))
+			}
+		}
+		return errs
+	}
+}
@@ -0,0 +1,50 @@
+package domonda
+
+import (
+	"testing"
+
+	"github.com/domonda/go-types/country"
+)
+
+func TestPartnerNormalizeContactRules(t *testing.T) {
+	newPartner := func() *Partner {
+		p := &Partner{}
+		p.Name.Set("Muster GmbH")
+		p.Country = country.AT.Nullable()
+		p.Street.Set("Hauptstr.12")
+		p.ZIP.Set("A-1010")
+		p.City.Set("Wien")
+		p.Phone.Set("01 234 56 78")
+		p.Website.Set("http://Example.com/")
+		return p
+	}
+
+	// The contact rules are opt-in and don't change the values by default
+	p := newPartner()
+	if errs := p.Normalize(false); len(errs) > 0 {
+		t.Fatalf("unexpected errors: %v", errs)
+	}
+	if want := newPartner(); p.Street != want.Street || p.ZIP != want.ZIP || p.Phone != want.Phone || p.Website != want.Website {
+		t.Errorf("Normalize changed contact fields: %q, %q, %q, %q", p.Street, p.ZIP, p.Phone, p.Website)
+	}
+
+	p = newPartner()
+	if errs := p.NormalizeWithRules(false, ContactPartnerRules()...); len(errs) > 0 {
+		t.Fatalf("unexpected errors: %v", errs)
+	}
+	if p.Street != "Hauptstraße 12" || p.ZIP != "1010" || p.Phone != "+4312345678" || p.Website != "https://example.com" {
+		t.Errorf("unexpected contact fields after ContactPartnerRules: %q, %q, %q, %q", p.Street, p.ZIP, p.Phone, p.Website)
+	}
+
+	// Values that can't be normalized are kept as warnings
+	p = newPartner()
+	p.Street.Set("Hauptplatz")
+	p.Phone.Set("1234")
+	errs := p.NormalizeWithRules(false, ContactPartnerRules()...)
+	if len(errs) != 2 || !IsWarning(errs[0]) || !IsWarning(errs[1]) {
+		t.Errorf("expected two warnings but got %v", errs)
+	}
+	if p.Street != "Hauptplatz" || p.Phone != "1234" {
+		t.Errorf("unexpected fields after warnings: %q, %q", p.Street, p.Phone)
+	}
+}
@@ -0,0 +1,57 @@
+package domonda
+
+import (
+	"errors"
+	"io"
+)
+
+// PartnerColumns are the default column names per Partner field
+// used by ReadPartnersCSV and ReadPartnersXLSX in addition to the field names themselves.
+// Column names are matched case-insensitive ignoring whitespace and punctuation.
+var PartnerColumns = map[string][]string{
+	"Name":                {"Firmenname", "Firma", "Name1", "Unternehmen", "Company", "Company Name", "Partner", "Partner Name"},
+	"AlternativeNames":    {"Alternative Names", "Alternativnamen", "Aliases", "Alias"},
+	"Street":              {"Straße", "Strasse", "Adresse", "Address", "Street Address"},
+	"City":                {"Ort", "Stadt", "Town"},
+	"ZIP":                 {"PLZ", "Postleitzahl", "Postal Code", "Zip Code", "Postcode"},
+	"Country":             {"Land", "Länderkennzeichen", "Ländercode", "Country Code", "Staat"},
+	"Phone":               {"Telefon", "Tel", "Telefonnummer", "Telephone", "Phone Number"},
+	"Email":               {"E-Mail", "Mail", "E-Mail-Adresse", "Email Address"},
+	"Website":             {"Webseite", "Homepage", "Internet", "Web", "URL"},
+	"CompRegNo":           {"Firmenbuchnummer", "FN", "Handelsregisternummer", "HRB", "Registernummer", "Company Registration Number"},
+	"TaxIDNo":             {"Steuernummer", "StNr", "Tax ID", "Tax Number"},
+	"VATIDNo":             {"UID", "UID-Nummer", "USt-IdNr", "UStID", "VAT ID", "VAT Number", "VAT"},
+	"VendorAccountNumber": {"Kreditorennummer", "Kreditor", "Kreditorennr", "Kreditorenkonto", "Lieferantennummer", "Vendor Account", "Vendor Number", "Creditor Number"},
+	"ClientAccountNumber": {"Debitorennummer", "Debitor", "Debitorennr", "Debitorenkonto", "Kundennummer", "Client Account", "Customer Number", "Debtor Number"},
+	"IBAN":                {"Bankverbindung", "Kontonummer IBAN"},
+	"BIC":                 {"SWIFT", "SWIFT-Code", "BIC/SWIFT"},
+}
+
+// ReadPartnersCSV reads partners from CSV data with a header line.
+//
+// The CSV columns are mapped to Partner fields by their header names
+// using config.Columns and PartnerColumns which includes
+// common German header names like "Firmenname", "UID", or "Kreditorennummer".
+// Multiple AlternativeNames in one column are separated by "|" or newlines.
+// The delimiter and encoding (UTF-8, Windows-1252) are detected
+// if not set in config, which can be nil for the defaults.
+//
+// Every partner is normalized with Partner.Normalize and rows with errors
+// other than warnings (see IsWarning)
+// are returned as RowError with their line number instead of as partner,
+// so the returned partners are ready for PostPartners.
+// The returned error is only non nil if the CSV could not be read at all.
+func ReadPartnersCSV(r io.Reader, config *CSVConfig) (partners []*Partner, rowErrs []*RowError, err error) {
+	return readCSV(r, config, PartnerColumns, func(p *Partner) error {
+		return errors.Join(withoutWarnings(p.Normalize(false))...)
+	})
+}
+
+// ReadPartnersXLSX reads partners from a worksheet of XLSX data
+// with a header row like ReadPartnersCSV.
+// The config can be nil for the defaults.
+func ReadPartnersXLSX(r io.Reader, config *XLSXConfig) (partners []*Partner, rowErrs []*RowError, err error) {
+	return readXLSX(r, config, PartnerColumns, func(p *Partner) error {
+		return errors.Join(withoutWarnings(p.Normalize(false))...)
+	})
+}
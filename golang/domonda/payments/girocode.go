@@ -0,0 +1,185 @@
+package payments
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/domonda/go-types/bank"
+	"github.com/domonda/go-types/money"
+	"github.com/skip2/go-qrcode"
+
+	"github.com/domonda/api/golang/domonda"
+)
+
+// Limits of EPC069-12 QR code fields
+const (
+	MaxGiroCodeNameLength        = 70
+	MaxGiroCodeReferenceLength   = 35
+	MaxGiroCodeRemittanceLength  = 140
+	MaxGiroCodeInformationLength = 70
+	MaxGiroCodePayloadLength     = 331
+	MaxGiroCodeAmount            = money.Amount(999999999.99)
+)
+
+// GiroCode is the payment data of an EPC QR code (EPC069-12)
+// also known as GiroCode that can be scanned by banking apps
+// to initiate a SEPA credit transfer in EUR.
+type GiroCode struct {
+	// BIC of the beneficiary's bank is optional within the EEA
+	BIC bank.NullableBIC
+
+	// Name of the beneficiary, max 70 characters
+	Name string
+
+	// IBAN of the beneficiary
+	IBAN bank.IBAN
+
+	// Amount in EUR or zero to let the payer enter the amount
+	Amount money.Amount
+
+	// Purpose is an optional 4 character purpose code like "GDDS"
+	Purpose string
+
+	// Reference is a structured creditor reference
+	// like "RF18539007547034", max 35 characters.
+	// Reference and RemittanceInfo must not be used together.
+	Reference string
+
+	// RemittanceInfo is the unstructured remittance
+	// information, max 140 characters
+	RemittanceInfo string
+
+	// Information is an optional hint
+	// for the payer, max 70 characters
+	Information string
+}
+
+// NewGiroCode returns the GiroCode to pay the invoice
+// with the IBAN, BIC, and Total of the invoice
+// and the InvoiceNumber as remittance info.
+//
+// The beneficiary is the PartnerName of the invoice
+// if beneficiaryName is empty.
+// Use the name of the issuing company as beneficiaryName
+// for outgoing invoices where the partner is the payer.
+// Invoices in other currencies than EUR and credit memos result in an error.
+func NewGiroCode(inv *domonda.Invoice, beneficiaryName string) (*GiroCode, error) {
+	if inv.CreditMemo != nil && *inv.CreditMemo {
+		return nil, errors.New("can't create GiroCode for credit memo")
+	}
+	if inv.Currency.IsNotNull() && inv.Currency.Get() != money.EUR {
+		return nil, fmt.Errorf("GiroCode only supports EUR, not %s", inv.Currency.Get())
+	}
+	if beneficiaryName == "" {
+		beneficiaryName = inv.PartnerName.String()
+	}
+	iban, err := bank.IBAN(inv.IBAN).Normalized()
+	if err != nil {
+		return nil, fmt.Errorf("invalid IBAN %q: %w", inv.IBAN, err)
+	}
+	bic, err := inv.BIC.Normalized()
+	if err != nil {
+		return nil, fmt.Errorf("invalid BIC %q: %w", inv.BIC, err)
+	}
+	g := &GiroCode{
+		BIC:            bic,
+		Name:           strings.TrimSpace(beneficiaryName),
+		IBAN:           iban,
+		RemittanceInfo: inv.InvoiceNumber.String(),
+	}
+	if inv.Total != nil {
+		g.Amount = inv.Total.RoundToCents()
+	}
+	return g, g.Validate()
+}
+
+// Validate checks the fields and the length limits of the GiroCode
+func (g *GiroCode) Validate() error {
+	var errs []error
+	if g.Name == "" {
+		errs = append(errs, errors.New("missing beneficiary name"))
+	}
+	if err := g.IBAN.Validate(); err != nil {
+		errs = append(errs, fmt.Errorf("invalid IBAN %q: %w", g.IBAN, err))
+	}
+	if err := g.BIC.Validate(); err != nil {
+		errs = append(errs, fmt.Errorf("invalid BIC %q: %w", g.BIC, err))
+	}
+	if g.Amount != 0 && (g.Amount < 0.01 || g.Amount > MaxGiroCodeAmount) {
+		errs = append(errs, fmt.Errorf("amount %s not in range of [0.01..%s]", g.Amount, MaxGiroCodeAmount))
+	}
+	if g.Purpose != "" && (len(g.Purpose) != 4 || strings.ToUpper(g.Purpose) != g.Purpose) {
+		errs = append(errs, fmt.Errorf("purpose code %q must have 4 upper case characters", g.Purpose))
+	}
+	if g.Reference != "" && g.RemittanceInfo != "" {
+		errs = append(errs, errors.New("reference and remittance info must not be used together"))
+	}
+	for _, field := range []struct {
+		name      string
+		value     string
+		maxLength int
+	}{
+		{"name", g.Name, MaxGiroCodeNameLength},
+		{"reference", g.Reference, MaxGiroCodeReferenceLength},
+		{"remittance info", g.RemittanceInfo, MaxGiroCodeRemittanceLength},
+		{"information", g.Information, MaxGiroCodeInformationLength},
+	} {
+		if strings.ContainsAny(field.value, "\r\n") {
+			errs = append(errs, fmt.Errorf("%s must not contain line breaks", field.name))
+		}
+		if n := utf8.RuneCountInString(field.value); n > field.maxLength {
+			errs = append(errs, fmt.Errorf("%s has %d characters, max is %d", field.name, n, field.maxLength))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Payload returns the text of the QR code in the EPC069-12 format
+// version 002 with UTF-8 character set.
+// Returns an error if the GiroCode is invalid
+// or the payload is longer than 331 bytes.
+func (g *GiroCode) Payload() (string, error) {
+	if err := g.Validate(); err != nil {
+		return "", err
+	}
+	var amount string
+	if g.Amount != 0 {
+		amount = fmt.Sprintf("EUR%.2f", float64(g.Amount.RoundToCents()))
+	}
+	lines := []string{
+		"BCD",
+		"002",
+		"1", // UTF-8
+		"SCT",
+		string(g.BIC),
+		g.Name,
+		string(g.IBAN),
+		amount,
+		g.Purpose,
+		g.Reference,
+		g.RemittanceInfo,
+		g.Information,
+	}
+	// Trailing empty lines are optional
+	for lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+	payload := strings.Join(lines, "\n")
+	if len(payload) > MaxGiroCodePayloadLength {
+		return "", fmt.Errorf("GiroCode payload has %d bytes, max is %d", len(payload), MaxGiroCodePayloadLength)
+	}
+	return payload, nil
+}
+
+// PNG returns the QR code of the payload as PNG image
+// with size width and height in pixels using
+// the error correction level M required by EPC069-12.
+func (g *GiroCode) PNG(size int) ([]byte, error) {
+	payload, err := g.Payload()
+	if err != nil {
+		return nil, err
+	}
+	return qrcode.Encode(payload, qrcode.Medium, size)
+}
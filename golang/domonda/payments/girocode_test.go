@@ -0,0 +1,143 @@
+package payments
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/domonda/go-types/money"
+)
+
+func TestGiroCodePayload(t *testing.T) {
+	for _, tt := range []struct {
+		name    string
+		code    GiroCode
+		want    string
+		wantErr string
+	}{
+		{
+			// Example of the EPC069-12 guidelines
+			name: "EPC example",
+			code: GiroCode{
+				BIC:            "BPOTBEB1",
+				Name:           "Red Cross of Belgium",
+				IBAN:           "BE72000000001616",
+				Amount:         1,
+				Purpose:        "CHAR",
+				RemittanceInfo: "Urgency fund",
+				Information:    "Sample EPC QR code",
+			},
+			want: "BCD\n002\n1\nSCT\nBPOTBEB1\nRed Cross of Belgium\nBE72000000001616\nEUR1.00\nCHAR\n\nUrgency fund\nSample EPC QR code",
+		},
+		{
+			name: "trailing empty lines trimmed",
+			code: GiroCode{
+				Name:   "Muster GmbH",
+				IBAN:   "DE89370400440532013000",
+				Amount: 1234.5,
+			},
+			want: "BCD\n002\n1\nSCT\n\nMuster GmbH\nDE89370400440532013000\nEUR1234.50",
+		},
+		{
+			name: "without amount and with reference",
+			code: GiroCode{
+				BIC:       "COBADEFFXXX",
+				Name:      "Muster GmbH",
+				IBAN:      "DE89370400440532013000",
+				Reference: "RF18539007547034",
+			},
+			want: "BCD\n002\n1\nSCT\nCOBADEFFXXX\nMuster GmbH\nDE89370400440532013000\n\n\nRF18539007547034",
+		},
+		{
+			name: "amount rounded to cents",
+			code: GiroCode{Name: "Muster GmbH", IBAN: "DE89370400440532013000", Amount: 0.014},
+			want: "BCD\n002\n1\nSCT\n\nMuster GmbH\nDE89370400440532013000\nEUR0.01",
+		},
+		{
+			name: "max amount",
+			code: GiroCode{Name: "Muster GmbH", IBAN: "DE89370400440532013000", Amount: MaxGiroCodeAmount},
+			want: "BCD\n002\n1\nSCT\n\nMuster GmbH\nDE89370400440532013000\nEUR999999999.99",
+		},
+		{
+			name:    "amount too large",
+			code:    GiroCode{Name: "Muster GmbH", IBAN: "DE89370400440532013000", Amount: MaxGiroCodeAmount + 0.01},
+			wantErr: "not in range",
+		},
+		{
+			name:    "negative amount",
+			code:    GiroCode{Name: "Muster GmbH", IBAN: "DE89370400440532013000", Amount: -1},
+			wantErr: "not in range",
+		},
+		{
+			name: "reference and remittance info",
+			code: GiroCode{
+				Name:           "Muster GmbH",
+				IBAN:           "DE89370400440532013000",
+				Reference:      "RF18539007547034",
+				RemittanceInfo: "RE-1",
+			},
+			wantErr: "must not be used together",
+		},
+		{
+			name:    "line break",
+			code:    GiroCode{Name: "Muster\nGmbH", IBAN: "DE89370400440532013000"},
+			wantErr: "line breaks",
+		},
+		{
+			name:    "invalid purpose",
+			code:    GiroCode{Name: "Muster GmbH", IBAN: "DE89370400440532013000", Purpose: "char"},
+			wantErr: "purpose code",
+		},
+		{
+			// All fields within their character limits,
+			// but the UTF-8 payload is longer than 331 bytes
+			name: "payload too long",
+			code: GiroCode{
+				BIC:            "COBADEFFXXX",
+				Name:           strings.Repeat("ä", MaxGiroCodeNameLength),
+				IBAN:           "DE89370400440532013000",
+				Amount:         MaxGiroCodeAmount,
+				RemittanceInfo: strings.Repeat("x", MaxGiroCodeRemittanceLength),
+				Information:    strings.Repeat("y", MaxGiroCodeInformationLength),
+			},
+			wantErr: "payload has",
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.code.Payload()
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("expected error with %q but got %v and payload %q", tt.wantErr, err, got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want {
+				t.Errorf("got payload\n%q\nexpected\n%q", got, tt.want)
+			}
+			if len(got) > MaxGiroCodePayloadLength {
+				t.Errorf("payload has %d bytes", len(got))
+			}
+		})
+	}
+}
+
+func TestNewGiroCode(t *testing.T) {
+	inv := testInvoice("RE-2024-17", "Muster GmbH", "DE89 3704 0044 0532 0130 00", "", 119.999)
+	code, err := NewGiroCode(inv, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if code.Name != "Muster GmbH" || code.IBAN != "DE89370400440532013000" || code.Amount != 120 || code.RemittanceInfo != "RE-2024-17" {
+		t.Errorf("unexpected GiroCode %+v", code)
+	}
+	if code, err = NewGiroCode(inv, "Issuer AG"); err != nil || code.Name != "Issuer AG" {
+		t.Errorf("expected beneficiary name Issuer AG but got %+v, %v", code, err)
+	}
+
+	inv.Currency = money.NullableCurrency(money.CHF)
+	if _, err := NewGiroCode(inv, ""); err == nil {
+		t.Error("expected error for CHF invoice")
+	}
+}
@@ -0,0 +1,363 @@
+package payments
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/domonda/go-types/bank"
+	"github.com/domonda/go-types/date"
+	"github.com/domonda/go-types/money"
+	"github.com/domonda/go-types/uu"
+
+	"github.com/domonda/api/golang/domonda"
+)
+
+// Pain001Namespace is the XML namespace of pain.001.001.09 documents
+const Pain001Namespace = "urn:iso:std:iso:20022:tech:xsd:pain.001.001.09"
+
+// Maximum lengths of pain.001 fields
+const (
+	MaxIDLength             = 35
+	MaxNameLength           = 70
+	MaxRemittanceInfoLength = 140
+)
+
+// CreditTransfer is a SEPA credit transfer initiation
+// of payments from one debtor bank account
+// that is written as pain.001.001.09 XML document.
+type CreditTransfer struct {
+	// MessageID is the unique ID of the message, max 35 characters
+	MessageID string
+
+	// CreationTime of the message
+	CreationTime time.Time
+
+	// Debtor is the bank account the payments are debited from
+	Debtor *domonda.BankAccount
+
+	// ExecutionDate is the requested execution date of the payments
+	ExecutionDate date.Date
+
+	// BatchBooking requests a single booking of all payments
+	// on the debtor account instead of one booking per payment
+	BatchBooking bool
+
+	// Transfers are the payments to the creditors
+	Transfers []*Transfer
+}
+
+// Transfer is a payment of a CreditTransfer
+type Transfer struct {
+	// EndToEndID identifies the payment from debtor
+	// to creditor, max 35 characters
+	EndToEndID string
+
+	// Amount of the payment in EUR
+	Amount money.Amount
+
+	// CreditorName is the name of the payee, max 70 characters
+	CreditorName string
+
+	// CreditorIBAN is the IBAN of the payee
+	CreditorIBAN bank.IBAN
+
+	// CreditorBIC of the payee's bank is optional for SEPA payments
+	CreditorBIC bank.NullableBIC
+
+	// RemittanceInfo is the unstructured purpose, max 140 characters
+	RemittanceInfo string
+
+	// Invoice paid by the transfer if created by NewCreditTransfer
+	Invoice *domonda.Invoice
+
+	// Discount deducted from the Total of the Invoice
+	Discount money.Amount
+}
+
+// NewCreditTransfer returns a CreditTransfer from the debtor bank account
+// executed at executionDate paying the invoices.
+//
+// The amount of every transfer is the Total of the invoice
+// minus the discount if executionDate is not after DiscountUntil.
+// The end to end ID and remittance info are created from the InvoiceNumber.
+// Nil invoices, credit memos, invoices in other currencies than EUR, and invoices without
+// Total, InvoiceNumber, PartnerName, or valid IBAN and BIC result in errors.
+// The returned CreditTransfer contains the transfers of the valid invoices
+// if it is returned together with errors for the other invoices.
+func NewCreditTransfer(debtor *domonda.BankAccount, executionDate date.Date, invoices []*domonda.Invoice) (*CreditTransfer, error) {
+	c := &CreditTransfer{
+		MessageID:     uu.IDv4().Hex(),
+		CreationTime:  time.Now(),
+		Debtor:        debtor,
+		ExecutionDate: executionDate,
+	}
+	var errs []error
+	for i, inv := range invoices {
+		if inv == nil {
+			errs = append(errs, fmt.Errorf("invoice %d is nil", i))
+			continue
+		}
+		transfer, err := NewTransfer(inv, executionDate)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("invoice %d %q: %w", i, inv.InvoiceNumber.String(), err))
+			continue
+		}
+		c.Transfers = append(c.Transfers, transfer)
+	}
+	return c, errors.Join(errs...)
+}
+
+// NewTransfer returns the Transfer paying the invoice at executionDate.
+// See NewCreditTransfer
+func NewTransfer(inv *domonda.Invoice, executionDate date.Date) (*Transfer, error) {
+	if inv == nil {
+		return nil, errors.New("missing invoice")
+	}
+	var errs []error
+	if inv.CreditMemo != nil && *inv.CreditMemo {
+		errs = append(errs, errors.New("can't pay credit memo"))
+	}
+	if inv.Total == nil || !inv.Total.ValidAndGreaterZero() {
+		errs = append(errs, errors.New("missing total amount"))
+	}
+	if inv.Currency.IsNotNull() && inv.Currency.Get() != money.EUR {
+		errs = append(errs, fmt.Errorf("currency %s is not EUR", inv.Currency.Get()))
+	}
+	endToEndID := sepaID(inv.InvoiceNumber.String(), MaxIDLength)
+	if inv.InvoiceNumber.IsNull() {
+		errs = append(errs, errors.New("missing invoice number for remittance info"))
+	} else if endToEndID == "" {
+		errs = append(errs, fmt.Errorf("invoice number %q can't be used as end to end ID", inv.InvoiceNumber))
+	}
+	if inv.PartnerName.IsNull() {
+		errs = append(errs, errors.New("missing partner name"))
+	}
+	iban, err := bank.IBAN(inv.IBAN).Normalized()
+	if inv.IBAN.IsNull() {
+		errs = append(errs, errors.New("missing IBAN"))
+	} else if err != nil {
+		errs = append(errs, fmt.Errorf("invalid IBAN %q: %w", inv.IBAN, err))
+	}
+	bic, err := inv.BIC.Normalized()
+	if err != nil {
+		errs = append(errs, fmt.Errorf("invalid BIC %q: %w", inv.BIC, err))
+	}
+	if len(errs) > 0 {
+		return nil, errors.Join(errs...)
+	}
+	amount, discount := inv.AmountDue(executionDate)
+	return &Transfer{
+		EndToEndID:     endToEndID,
+		Amount:         amount,
+		CreditorName:   SEPAText(inv.PartnerName.String(), MaxNameLength),
+		CreditorIBAN:   iban,
+		CreditorBIC:    bic,
+		RemittanceInfo: SEPAText(inv.InvoiceNumber.String(), MaxRemittanceInfoLength),
+		Invoice:        inv,
+		Discount:       discount,
+	}, nil
+}
+
+// ControlSum returns the sum of the amounts of all transfers
+func (c *CreditTransfer) ControlSum() money.Amount {
+	var sum money.Amount
+	for _, t := range c.Transfers {
+		sum += t.Amount.RoundToCents()
+	}
+	return sum.RoundToCents()
+}
+
+// Validate checks the debtor, the IDs, and all transfers
+func (c *CreditTransfer) Validate() error {
+	var errs []error
+	if err := validSEPAID(c.MessageID, MaxIDLength); err != nil {
+		errs = append(errs, fmt.Errorf("invalid message ID: %w", err))
+	}
+	if c.Debtor == nil {
+		errs = append(errs, errors.New("missing debtor bank account"))
+	} else {
+		if err := c.Debtor.IBAN.Validate(); err != nil {
+			errs = append(errs, fmt.Errorf("invalid debtor IBAN %q: %w", c.Debtor.IBAN, err))
+		}
+		if c.Debtor.BIC != "" && !c.Debtor.BIC.Valid() {
+			errs = append(errs, fmt.Errorf("invalid debtor BIC %q", c.Debtor.BIC))
+		}
+		if c.Debtor.Currency != "" && c.Debtor.Currency != money.EUR {
+			errs = append(errs, fmt.Errorf("debtor account currency %s is not EUR", c.Debtor.Currency))
+		}
+		if c.Debtor.Holder.IsEmpty() {
+			errs = append(errs, errors.New("missing debtor account holder"))
+		}
+	}
+	if err := c.ExecutionDate.Validate(); err != nil {
+		errs = append(errs, fmt.Errorf("invalid execution date: %w", err))
+	}
+	if len(c.Transfers) == 0 {
+		errs = append(errs, errors.New("no transfers"))
+	}
+	for i, t := range c.Transfers {
+		if err := t.Validate(); err != nil {
+			errs = append(errs, fmt.Errorf("transfer %d: %w", i, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Validate checks the fields of the transfer
+func (t *Transfer) Validate() error {
+	var errs []error
+	if err := validSEPAID(t.EndToEndID, MaxIDLength); err != nil {
+		errs = append(errs, fmt.Errorf("invalid end to end ID: %w", err))
+	}
+	if !t.Amount.ValidAndGreaterZero() || t.Amount > 999999999.99 {
+		errs = append(errs, fmt.Errorf("invalid amount %s", t.Amount))
+	}
+	if t.CreditorName == "" || len(t.CreditorName) > MaxNameLength {
+		errs = append(errs, fmt.Errorf("creditor name %q must have 1 to %d characters", t.CreditorName, MaxNameLength))
+	}
+	if err := t.CreditorIBAN.Validate(); err != nil {
+		errs = append(errs, fmt.Errorf("invalid creditor IBAN %q: %w", t.CreditorIBAN, err))
+	}
+	if err := t.CreditorBIC.Validate(); err != nil {
+		errs = append(errs, fmt.Errorf("invalid creditor BIC %q: %w", t.CreditorBIC, err))
+	}
+	if len(t.RemittanceInfo) > MaxRemittanceInfoLength {
+		errs = append(errs, fmt.Errorf("remittance info %q longer than %d characters", t.RemittanceInfo, MaxRemittanceInfoLength))
+	}
+	return errors.Join(errs...)
+}
+
+// XML returns the pain.001.001.09 XML document of the credit transfer
+func (c *CreditTransfer) XML() ([]byte, error) {
+	var buf bytes.Buffer
+	if err := c.WriteXML(&buf); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// WriteXML validates the credit transfer and writes
+// it as pain.001.001.09 XML document to w
+func (c *CreditTransfer) WriteXML(w io.Writer) error {
+	if err := c.Validate(); err != nil {
+		return err
+	}
+	controlSum := amountString(c.ControlSum())
+	debtorName := SEPAText(c.Debtor.Holder.String(), MaxNameLength)
+	doc := pain001Document{
+		Namespace: Pain001Namespace,
+		GroupHeader: pain001GroupHeader{
+			MessageID:            c.MessageID,
+			CreationDateTime:     c.CreationTime.Format("2006-01-02T15:04:05"),
+			NumberOfTransactions: len(c.Transfers),
+			ControlSum:           controlSum,
+			InitiatingPartyName:  debtorName,
+		},
+		PaymentInfo: pain001PaymentInfo{
+			ID:                   c.MessageID,
+			Method:               "TRF",
+			BatchBooking:         c.BatchBooking,
+			NumberOfTransactions: len(c.Transfers),
+			ControlSum:           controlSum,
+			ServiceLevel:         "SEPA",
+			ExecutionDate:        string(c.ExecutionDate),
+			DebtorName:           debtorName,
+			DebtorIBAN:           string(c.Debtor.IBAN),
+			DebtorAgent:          newPain001Agent(bank.NullableBIC(c.Debtor.BIC)),
+			ChargeBearer:         "SLEV",
+		},
+	}
+	for _, t := range c.Transfers {
+		tx := pain001Transaction{
+			EndToEndID:     t.EndToEndID,
+			Amount:         pain001Amount{Currency: "EUR", Value: amountString(t.Amount)},
+			CreditorName:   t.CreditorName,
+			CreditorIBAN:   string(t.CreditorIBAN),
+			RemittanceInfo: t.RemittanceInfo,
+		}
+		if t.CreditorBIC.IsNotNull() {
+			tx.CreditorAgent = newPain001Agent(t.CreditorBIC)
+		}
+		doc.PaymentInfo.Transactions = append(doc.PaymentInfo.Transactions, tx)
+	}
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	if err := enc.Encode(doc); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
+
+func amountString(amount money.Amount) string {
+	return fmt.Sprintf("%.2f", float64(amount.RoundToCents()))
+}
+
+type pain001Document struct {
+	XMLName     xml.Name           `xml:"Document"`
+	Namespace   string             `xml:"xmlns,attr"`
+	GroupHeader pain001GroupHeader `xml:"CstmrCdtTrfInitn>GrpHdr"`
+	PaymentInfo pain001PaymentInfo `xml:"CstmrCdtTrfInitn>PmtInf"`
+}
+
+type pain001GroupHeader struct {
+	MessageID            string `xml:"MsgId"`
+	CreationDateTime     string `xml:"CreDtTm"`
+	NumberOfTransactions int    `xml:"NbOfTxs"`
+	ControlSum           string `xml:"CtrlSum"`
+	InitiatingPartyName  string `xml:"InitgPty>Nm"`
+}
+
+type pain001PaymentInfo struct {
+	ID                   string               `xml:"PmtInfId"`
+	Method               string               `xml:"PmtMtd"`
+	BatchBooking         bool                 `xml:"BtchBookg"`
+	NumberOfTransactions int                  `xml:"NbOfTxs"`
+	ControlSum           string               `xml:"CtrlSum"`
+	ServiceLevel         string               `xml:"PmtTpInf>SvcLvl>Cd"`
+	ExecutionDate        string               `xml:"ReqdExctnDt>Dt"`
+	DebtorName           string               `xml:"Dbtr>Nm"`
+	DebtorIBAN           string               `xml:"DbtrAcct>Id>IBAN"`
+	DebtorAgent          *pain001Agent        `xml:"DbtrAgt"`
+	ChargeBearer         string               `xml:"ChrgBr"`
+	Transactions         []pain001Transaction `xml:"CdtTrfTxInf"`
+}
+
+type pain001Agent struct {
+	BIC   string             `xml:"FinInstnId>BICFI,omitempty"`
+	Other *pain001OtherAgent `xml:"FinInstnId>Othr,omitempty"`
+}
+
+type pain001OtherAgent struct {
+	ID string `xml:"Id"`
+}
+
+// newPain001Agent returns the agent with the BIC
+// or "NOTPROVIDED" if bic is null
+func newPain001Agent(bic bank.NullableBIC) *pain001Agent {
+	if bic.IsNull() {
+		return &pain001Agent{Other: &pain001OtherAgent{ID: "NOTPROVIDED"}}
+	}
+	return &pain001Agent{BIC: string(bic)}
+}
+
+type pain001Amount struct {
+	Currency string `xml:"Ccy,attr"`
+	Value    string `xml:",chardata"`
+}
+
+type pain001Transaction struct {
+	EndToEndID     string        `xml:"PmtId>EndToEndId"`
+	Amount         pain001Amount `xml:"Amt>InstdAmt"`
+	CreditorAgent  *pain001Agent `xml:"CdtrAgt,omitempty"`
+	CreditorName   string        `xml:"Cdtr>Nm"`
+	CreditorIBAN   string        `xml:"CdtrAcct>Id>IBAN"`
+	RemittanceInfo string        `xml:"RmtInf>Ustrd,omitempty"`
+}
@@ -0,0 +1,185 @@
+package payments
+
+import (
+	"flag"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/domonda/go-types/bank"
+	"github.com/domonda/go-types/date"
+	"github.com/domonda/go-types/money"
+	"github.com/domonda/go-types/nullable"
+
+	"github.com/domonda/api/golang/domonda"
+)
+
+var update = flag.Bool("update", false, "update the golden files in testdata")
+
+func testInvoice(number, partner string, iban bank.NullableIBAN, bic bank.NullableBIC, total money.Amount) *domonda.Invoice {
+	return &domonda.Invoice{
+		InvoiceNumber: nullable.TrimmedString(number),
+		PartnerName:   nullable.TrimmedString(partner),
+		Currency:      money.NullableCurrency(money.EUR),
+		Total:         total.Ptr(),
+		IBAN:          iban,
+		BIC:           bic,
+	}
+}
+
+func TestCreditTransferWriteXML(t *testing.T) {
+	debtor := &domonda.BankAccount{
+		IBAN:     "DE89370400440532013000",
+		BIC:      "COBADEFFXXX",
+		Currency: money.EUR,
+		Holder:   "Müller & Söhne GmbH",
+	}
+	rate := money.Rate(2)
+	discounted := testInvoice("RE-2024/17", "Bäckerei Größl", "AT611904300234573201", "", 100)
+	discounted.DiscountPercent = &rate
+	discounted.DiscountUntil = "2024-03-10"
+	c, err := NewCreditTransfer(debtor, "2024-03-05", []*domonda.Invoice{
+		discounted,
+		testInvoice("/4711//1/", "Muster AG", "CH9300762011623852957", "UBSWCHZH80A", 1234.5),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	c.MessageID = "MSG-1"
+	c.CreationTime = time.Date(2024, 3, 4, 12, 30, 0, 0, time.UTC)
+	c.BatchBooking = true
+
+	xml, err := c.XML()
+	if err != nil {
+		t.Fatal(err)
+	}
+	const golden = "testdata/pain001.xml"
+	if *update {
+		if err := os.WriteFile(golden, xml, 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	expected, err := os.ReadFile(golden)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(xml) != string(expected) {
+		t.Errorf("XML different from %s:\n%s", golden, xml)
+	}
+}
+
+func TestNewTransfer(t *testing.T) {
+	rate := money.Rate(3)
+	inv := testInvoice("RE-1", "Muster GmbH", "DE89370400440532013000", "", 200)
+	inv.DiscountPercent = &rate
+	inv.DiscountUntil = "2024-03-10"
+
+	for _, tt := range []struct {
+		date         date.Date
+		wantAmount   money.Amount
+		wantDiscount money.Amount
+	}{
+		{"2024-03-05", 194, 6},
+		{"2024-03-10", 194, 6},
+		{"2024-03-11", 200, 0},
+	} {
+		transfer, err := NewTransfer(inv, tt.date)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if transfer.Amount != tt.wantAmount || transfer.Discount != tt.wantDiscount {
+			t.Errorf("at %s got amount %s with discount %s, expected %s with discount %s", tt.date, transfer.Amount, transfer.Discount, tt.wantAmount, tt.wantDiscount)
+		}
+	}
+
+	transfer, err := NewTransfer(testInvoice("RE-2", "Muster GmbH", "DE89370400440532013000", "", 99.99), "2024-03-05")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if transfer.Amount != 99.99 || transfer.Discount != 0 || transfer.CreditorBIC.IsNotNull() {
+		t.Errorf("unexpected transfer without discount %+v", transfer)
+	}
+
+	creditMemo := true
+	invalid := testInvoice("///", "", "DE00370400440532013000", "", 100)
+	invalid.CreditMemo = &creditMemo
+	_, err = NewTransfer(invalid, "2024-03-05")
+	if err == nil {
+		t.Fatal("expected error")
+	}
+	for _, want := range []string{"credit memo", "end to end ID", "partner name", "invalid IBAN"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("expected error with %q but got %v", want, err)
+		}
+	}
+
+	if _, err := NewTransfer(nil, "2024-03-05"); err == nil {
+		t.Error("expected error for nil invoice")
+	}
+}
+
+func TestNewCreditTransferNilInvoice(t *testing.T) {
+	debtor := &domonda.BankAccount{IBAN: "DE89370400440532013000", Currency: money.EUR, Holder: "Muster GmbH"}
+	c, err := NewCreditTransfer(debtor, "2024-03-05", []*domonda.Invoice{
+		nil,
+		testInvoice("RE-1", "Muster AG", "AT611904300234573201", "", 10),
+	})
+	if err == nil || !strings.Contains(err.Error(), "invoice 0 is nil") {
+		t.Errorf("expected error for nil invoice but got %v", err)
+	}
+	if len(c.Transfers) != 1 {
+		t.Errorf("expected transfer of the valid invoice but got %d transfers", len(c.Transfers))
+	}
+}
+
+func TestSEPAText(t *testing.T) {
+	for _, tt := range []struct {
+		s         string
+		maxLength int
+		want      string
+	}{
+		{"Müller & Söhne GmbH", 0, "Mueller + Soehne GmbH"},
+		{"ÄÖÜ äöü ß", 0, "AeOeUe aeoeue ss"},
+		{"Crème Brûlée Café", 0, "Creme Brulee Cafe"},
+		{"Łódź Dvořák", 0, "Lodz Dvorak"},
+		{"Rechnung #17; Kunde: 4711 ", 0, "Rechnung 17 Kunde: 4711"},
+		{"a\t\nb  c", 0, "a b c"},
+		{"Ελλάδα 1", 0, "1"},
+		{"Müller GmbH", 6, "Muelle"},
+		{"Max Mustermann", 4, "Max"},
+	} {
+		if got := SEPAText(tt.s, tt.maxLength); got != tt.want {
+			t.Errorf("SEPAText(%q, %d) = %q, expected %q", tt.s, tt.maxLength, got, tt.want)
+		}
+	}
+}
+
+func TestSEPAID(t *testing.T) {
+	for _, tt := range []struct {
+		s         string
+		maxLength int
+		want      string
+	}{
+		{"RE 2024/17", 35, "RE2024/17"},
+		{"/RE//2024///17/", 35, "RE/2024/17"},
+		{"//", 35, ""},
+		{"RE/2024", 3, "RE"},
+		{"Größe", 35, "Groesse"},
+	} {
+		id := sepaID(tt.s, tt.maxLength)
+		if id != tt.want {
+			t.Errorf("sepaID(%q, %d) = %q, expected %q", tt.s, tt.maxLength, id, tt.want)
+		}
+		if id != "" {
+			if err := validSEPAID(id, tt.maxLength); err != nil {
+				t.Errorf("sepaID(%q, %d) returned invalid ID: %s", tt.s, tt.maxLength, err)
+			}
+		}
+	}
+	for _, id := range []string{"", "/RE", "RE/", "RE//1", "RE 1", "RE_1", strings.Repeat("1", 36)} {
+		if err := validSEPAID(id, MaxIDLength); err == nil {
+			t.Errorf("expected error for ID %q", id)
+		}
+	}
+}
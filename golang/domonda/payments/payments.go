@@ -0,0 +1,85 @@
+// Package payments creates SEPA credit transfers (pain.001)
+// to pay invoices from a debtor bank account,
+// creates EPC payment QR codes (GiroCode) for invoices,
+// and parses Swiss QR-bills and EPC QR codes.
+package payments
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/domonda/api/golang/domonda/partnername"
+)
+
+// sepaCharacters are the characters of the Latin character set
+// that all banks have to support for SEPA payments
+const sepaCharacters = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789/-?:().,'+ "
+
+// SEPAText returns s converted to the Latin character set of SEPA payments
+// with umlauts and accented letters replaced like "ä" with "ae",
+// "&" replaced with "+", other characters replaced with spaces,
+// whitespace collapsed, and truncated to maxLength characters
+// if maxLength is greater zero.
+func SEPAText(s string, maxLength int) string {
+	var b strings.Builder
+	for _, r := range s {
+		switch {
+		case strings.ContainsRune(sepaCharacters, r):
+			b.WriteRune(r)
+		case r == '&':
+			b.WriteRune('+')
+		case unicode.IsLetter(r):
+			folded := partnername.Fold(string(r))
+			if strings.Trim(folded, sepaCharacters) != "" {
+				b.WriteRune(' ')
+				break
+			}
+			if unicode.IsUpper(r) {
+				first, size := utf8.DecodeRuneInString(folded)
+				folded = string(unicode.ToUpper(first)) + folded[size:]
+			}
+			b.WriteString(folded)
+		default:
+			b.WriteRune(' ')
+		}
+	}
+	text := strings.Join(strings.Fields(b.String()), " ")
+	if maxLength > 0 && len(text) > maxLength {
+		text = strings.TrimSpace(text[:maxLength])
+	}
+	return text
+}
+
+// sepaID returns s converted with SEPAText without spaces
+// to be used as identifier like the end to end ID.
+// Because SEPA identifiers must not begin or end with "/"
+// and must not contain "//", multiple slashes are replaced
+// with one and leading and trailing slashes are removed.
+func sepaID(s string, maxLength int) string {
+	id := strings.ReplaceAll(SEPAText(s, 0), " ", "")
+	for strings.Contains(id, "//") {
+		id = strings.ReplaceAll(id, "//", "/")
+	}
+	id = strings.Trim(id, "/")
+	if len(id) > maxLength {
+		id = strings.TrimRight(id[:maxLength], "/")
+	}
+	return id
+}
+
+// validSEPAID returns an error if id is empty, longer than maxLength,
+// has characters that are not allowed for SEPA payments,
+// begins or ends with "/", or contains "//"
+func validSEPAID(id string, maxLength int) error {
+	switch {
+	case id == "" || len(id) > maxLength:
+		return fmt.Errorf("%q must have 1 to %d characters", id, maxLength)
+	case strings.Trim(id, sepaCharacters) != "" || strings.Contains(id, " "):
+		return fmt.Errorf("%q has characters that are not allowed", id)
+	case strings.HasPrefix(id, "/") || strings.HasSuffix(id, "/") || strings.Contains(id, "//"):
+		return fmt.Errorf("%q must not begin or end with '/' or contain '//'", id)
+	}
+	return nil
+}
@@ -0,0 +1,376 @@
+package payments
+
+//go:generate go tool go-enum $GOFILE
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/domonda/go-types/bank"
+	"github.com/domonda/go-types/country"
+	"github.com/domonda/go-types/money"
+	"github.com/domonda/go-types/notnull"
+	"github.com/domonda/go-types/nullable"
+
+	"github.com/domonda/api/golang/domonda"
+)
+
+// QRCodeFormat is the format of a payment QR code
+type QRCodeFormat string //#enum
+
+const (
+	// QRCodeFormatSwissQRBill is the Swiss QR-bill beginning with "SPC"
+	QRCodeFormatSwissQRBill QRCodeFormat = "SPC"
+
+	// QRCodeFormatEPC is the EPC QR code (GiroCode) beginning with "BCD"
+	QRCodeFormatEPC QRCodeFormat = "BCD"
+)
+
+// Valid indicates if f is any of the valid values for QRCodeFormat
+func (f QRCodeFormat) Valid() bool {
+	switch f {
+	case
+		QRCodeFormatSwissQRBill,
+		QRCodeFormatEPC:
+		return true
+	}
+	return false
+}
+
+// Validate returns an error if f is none of the valid values for QRCodeFormat
+func (f QRCodeFormat) Validate() error {
+	if !f.Valid() {
+		return fmt.Errorf("invalid value %#v for type payments.QRCodeFormat", f)
+	}
+	return nil
+}
+
+// Enums returns all valid values for QRCodeFormat
+func (QRCodeFormat) Enums() []QRCodeFormat {
+	return []QRCodeFormat{
+		QRCodeFormatSwissQRBill,
+		QRCodeFormatEPC,
+	}
+}
+
+// EnumStrings returns all valid values for QRCodeFormat as strings
+func (QRCodeFormat) EnumStrings() []string {
+	return []string{
+		"SPC",
+		"BCD",
+	}
+}
+
+// String implements the fmt.Stringer interface for QRCodeFormat
+func (f QRCodeFormat) String() string {
+	return string(f)
+}
+
+// Reference types of Swiss QR-bills
+const (
+	// ReferenceTypeQRR is the 27 digit QR reference
+	ReferenceTypeQRR = "QRR"
+
+	// ReferenceTypeSCOR is the ISO 11649 creditor reference like "RF18539007547034"
+	ReferenceTypeSCOR = "SCOR"
+
+	// ReferenceTypeNON is used for payments without reference
+	ReferenceTypeNON = "NON"
+)
+
+// QRAddress is the address of a creditor or debtor of a QRPayment
+type QRAddress struct {
+	Name    string
+	Street  string // Street with house number
+	ZIP     string
+	City    string
+	Country country.Code
+}
+
+// QRPayment is the payment data of a Swiss QR-bill
+// or EPC QR code (GiroCode) parsed by ParseQRPayment
+type QRPayment struct {
+	// Format of the QR code
+	Format QRCodeFormat
+
+	// IBAN of the creditor
+	IBAN bank.IBAN
+
+	// BIC of the creditor's bank, only available for EPC QR codes
+	BIC bank.NullableBIC
+
+	// Amount or nil if the payer has to enter the amount
+	Amount *money.Amount
+
+	// Currency of the amount, CHF or EUR for Swiss QR-bills
+	Currency money.Currency
+
+	// Creditor is the payee, EPC QR codes only contain the name
+	Creditor QRAddress
+
+	// Debtor is the payer if available, only used by Swiss QR-bills
+	Debtor QRAddress
+
+	// ReferenceType is QRR, SCOR, or NON for Swiss QR-bills,
+	// SCOR or empty for EPC QR codes
+	ReferenceType string
+
+	// Reference is the QR reference or creditor reference
+	Reference string
+
+	// RemittanceInfo is the unstructured message
+	RemittanceInfo string
+
+	// BillInformation is the structured bill information of Swiss QR-bills
+	// like "//S1/10/10201409/11/190512/20/1400.000-53/30/106017086"
+	BillInformation string
+}
+
+// ParseQRPayment parses the text of a Swiss QR-bill (SPC version 0200)
+// or EPC QR code (BCD version 001 or 002) and validates
+// the IBAN, the amount, and the reference.
+func ParseQRPayment(payload string) (*QRPayment, error) {
+	lines := strings.Split(strings.ReplaceAll(strings.TrimPrefix(payload, "\uFEFF"), "\r\n", "\n"), "\n")
+	for i := range lines {
+		lines[i] = strings.TrimSpace(lines[i])
+	}
+	switch QRCodeFormat(lines[0]) {
+	case QRCodeFormatSwissQRBill:
+		return parseSwissQRBill(lines)
+	case QRCodeFormatEPC:
+		return parseEPCQRCode(lines)
+	}
+	return nil, fmt.Errorf("unsupported payment QR code beginning with %q", lines[0])
+}
+
+func parseSwissQRBill(lines []string) (*QRPayment, error) {
+	if len(lines) < 31 {
+		return nil, fmt.Errorf("Swiss QR-bill has %d lines, expected at least 31", len(lines))
+	}
+	if lines[1] != "0200" {
+		return nil, fmt.Errorf("unsupported Swiss QR-bill version %q", lines[1])
+	}
+	if lines[30] != "EPD" {
+		return nil, fmt.Errorf("Swiss QR-bill has trailer %q instead of \"EPD\"", lines[30])
+	}
+	var errs []error
+	p := &QRPayment{
+		Format:         QRCodeFormatSwissQRBill,
+		Currency:       money.Currency(lines[19]),
+		ReferenceType:  lines[27],
+		Reference:      lines[28],
+		RemittanceInfo: lines[29],
+	}
+	if len(lines) > 31 {
+		p.BillInformation = lines[31]
+	}
+	var err error
+	if p.IBAN, err = bank.NormalizeIBAN(lines[3]); err != nil {
+		errs = append(errs, fmt.Errorf("invalid IBAN %q: %w", lines[3], err))
+	} else if c := p.IBAN.CountryCode(); c != country.CH && c != country.LI {
+		errs = append(errs, fmt.Errorf("IBAN %q is not from Switzerland or Liechtenstein", p.IBAN))
+	}
+	if p.Creditor, err = parseSwissQRAddress(lines[4:11]); err != nil {
+		errs = append(errs, fmt.Errorf("creditor: %w", err))
+	}
+	if p.Debtor, err = parseSwissQRAddress(lines[20:27]); err != nil {
+		errs = append(errs, fmt.Errorf("debtor: %w", err))
+	}
+	if lines[18] != "" {
+		amount, err := parseQRAmount(lines[18])
+		if err != nil {
+			errs = append(errs, err)
+		}
+		p.Amount = &amount
+	}
+	if p.Currency != money.CHF && p.Currency != money.EUR {
+		errs = append(errs, fmt.Errorf("currency %q is not CHF or EUR", p.Currency))
+	}
+	switch p.ReferenceType {
+	case ReferenceTypeQRR:
+		if !ValidQRReference(p.Reference) {
+			errs = append(errs, fmt.Errorf("invalid QR reference %q", p.Reference))
+		}
+	case ReferenceTypeSCOR:
+		if !ValidCreditorReference(p.Reference) {
+			errs = append(errs, fmt.Errorf("invalid creditor reference %q", p.Reference))
+		}
+	case ReferenceTypeNON:
+		if p.Reference != "" {
+			errs = append(errs, fmt.Errorf("reference %q with reference type NON", p.Reference))
+		}
+	default:
+		errs = append(errs, fmt.Errorf("invalid reference type %q", p.ReferenceType))
+	}
+	return p, errors.Join(errs...)
+}
+
+// parseSwissQRAddress parses the 7 lines of an address
+// of the structured type "S" or the combined type "K"
+func parseSwissQRAddress(lines []string) (QRAddress, error) {
+	addressType, address := lines[0], QRAddress{Name: lines[1], Country: country.Code(lines[6])}
+	switch addressType {
+	case "":
+		return address, nil
+	case "S":
+		address.Street = strings.TrimSpace(lines[2] + " " + lines[3])
+		address.ZIP = lines[4]
+		address.City = lines[5]
+	case "K":
+		address.Street = lines[2]
+		address.ZIP, address.City = domonda.SplitZIPCity(lines[3])
+	default:
+		return address, fmt.Errorf("invalid address type %q", addressType)
+	}
+	if !address.Country.Valid() {
+		return address, fmt.Errorf("invalid country %q", address.Country)
+	}
+	return address, nil
+}
+
+func parseEPCQRCode(lines []string) (*QRPayment, error) {
+	if len(lines) < 7 {
+		return nil, fmt.Errorf("EPC QR code has %d lines, expected at least 7", len(lines))
+	}
+	if lines[1] != "001" && lines[1] != "002" {
+		return nil, fmt.Errorf("unsupported EPC QR code version %q", lines[1])
+	}
+	if lines[3] != "SCT" {
+		return nil, fmt.Errorf("unsupported EPC QR code identification %q", lines[3])
+	}
+	line := func(i int) string {
+		if i < len(lines) {
+			return lines[i]
+		}
+		return ""
+	}
+	var errs []error
+	p := &QRPayment{
+		Format:         QRCodeFormatEPC,
+		Currency:       money.EUR,
+		Creditor:       QRAddress{Name: line(5)},
+		Reference:      line(9),
+		RemittanceInfo: line(10),
+	}
+	var err error
+	if p.IBAN, err = bank.NormalizeIBAN(line(6)); err != nil {
+		errs = append(errs, fmt.Errorf("invalid IBAN %q: %w", line(6), err))
+	}
+	if p.BIC, err = bank.NullableBIC(line(4)).Normalized(); err != nil {
+		errs = append(errs, fmt.Errorf("invalid BIC %q: %w", line(4), err))
+	}
+	if p.BIC.IsNull() && lines[1] == "001" {
+		errs = append(errs, errors.New("BIC is required for EPC QR code version 001"))
+	}
+	if amount := line(7); amount != "" {
+		if !strings.HasPrefix(amount, "EUR") {
+			errs = append(errs, fmt.Errorf("amount %q not in EUR", amount))
+		} else {
+			a, err := parseQRAmount(amount[3:])
+			if err != nil {
+				errs = append(errs, err)
+			}
+			p.Amount = &a
+		}
+	}
+	if p.Reference != "" {
+		p.ReferenceType = ReferenceTypeSCOR
+		if !ValidCreditorReference(p.Reference) {
+			errs = append(errs, fmt.Errorf("invalid creditor reference %q", p.Reference))
+		}
+	}
+	return p, errors.Join(errs...)
+}
+
+func parseQRAmount(s string) (money.Amount, error) {
+	f, err := strconv.ParseFloat(s, 64)
+	if err != nil || f < 0 {
+		return 0, fmt.Errorf("invalid amount %q", s)
+	}
+	return money.Amount(f), nil
+}
+
+// ValidQRReference returns if reference is a Swiss QR reference
+// of 27 digits with the recursive modulo 10 check digit
+func ValidQRReference(reference string) bool {
+	reference = strings.ReplaceAll(reference, " ", "")
+	if len(reference) != 27 || strings.Trim(reference, "0123456789") != "" {
+		return false
+	}
+	table := [10]int{0, 9, 4, 6, 8, 2, 7, 1, 3, 5}
+	carry := 0
+	for _, r := range reference[:26] {
+		carry = table[(carry+int(r-'0'))%10]
+	}
+	return (10-carry)%10 == int(reference[26]-'0')
+}
+
+// ValidCreditorReference returns if reference is an ISO 11649
+// creditor reference like "RF18539007547034" with valid check digits
+func ValidCreditorReference(reference string) bool {
+	reference = strings.ToUpper(strings.ReplaceAll(reference, " ", ""))
+	if len(reference) < 5 || len(reference) > 25 || !strings.HasPrefix(reference, "RF") {
+		return false
+	}
+	remainder := 0
+	for _, r := range reference[4:] + reference[:4] {
+		switch {
+		case r >= '0' && r <= '9':
+			remainder = (remainder*10 + int(r-'0')) % 97
+		case r >= 'A' && r <= 'Z':
+			remainder = (remainder*100 + int(r-'A') + 10) % 97
+		default:
+			return false
+		}
+	}
+	return remainder == 1
+}
+
+// FillInvoice sets the IBAN, BIC, Total, Currency, PartnerName,
+// and PartnerCountry of the invoice to the values of the payment
+// that are not empty.
+// The reference and the address of the creditor have no invoice
+// fields, use Partner to create the partner with the address.
+func (p *QRPayment) FillInvoice(inv *domonda.Invoice) {
+	if p.IBAN != "" {
+		inv.IBAN = p.IBAN.Nullable()
+	}
+	if p.BIC.IsNotNull() {
+		inv.BIC = p.BIC
+	}
+	if p.Amount != nil {
+		inv.Total = p.Amount.RoundToCents().Ptr()
+	}
+	if p.Currency != "" {
+		inv.Currency = money.NullableCurrency(p.Currency)
+	}
+	if p.Creditor.Name != "" {
+		inv.PartnerName = nullable.TrimmedString(p.Creditor.Name)
+	}
+	if p.Creditor.Country != "" {
+		inv.PartnerCountry = p.Creditor.Country.Nullable()
+	}
+}
+
+// Invoice returns a new invoice filled with FillInvoice
+func (p *QRPayment) Invoice() *domonda.Invoice {
+	inv := new(domonda.Invoice)
+	p.FillInvoice(inv)
+	return inv
+}
+
+// Partner returns the creditor of the payment as partner
+// with address and bank account
+func (p *QRPayment) Partner() *domonda.Partner {
+	return &domonda.Partner{
+		Name:    notnull.TrimmedString(p.Creditor.Name),
+		Street:  nullable.TrimmedString(p.Creditor.Street),
+		City:    nullable.TrimmedString(p.Creditor.City),
+		ZIP:     nullable.TrimmedString(p.Creditor.ZIP),
+		Country: p.Creditor.Country.Nullable(),
+		IBAN:    p.IBAN.Nullable(),
+		BIC:     p.BIC,
+	}
+}
@@ -0,0 +1,242 @@
+package payments
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/domonda/go-types/money"
+)
+
+func readQRPayment(t *testing.T, filename string) (*QRPayment, error) {
+	t.Helper()
+	data, err := os.ReadFile("testdata/" + filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return ParseQRPayment(string(data))
+}
+
+func TestParseQRPaymentSwissQRBill(t *testing.T) {
+	// Example of the Swiss Implementation Guidelines for the QR-bill
+	p, err := readQRPayment(t, "qrbill-qrr.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p.Format != QRCodeFormatSwissQRBill || p.IBAN != "CH4431999123000889012" || p.BIC.IsNotNull() {
+		t.Errorf("got format %q, IBAN %q, BIC %q", p.Format, p.IBAN, p.BIC)
+	}
+	if p.Amount == nil || *p.Amount != 1949.75 || p.Currency != money.CHF {
+		t.Errorf("got amount %v %s, expected 1949.75 CHF", p.Amount, p.Currency)
+	}
+	expectedCreditor := QRAddress{Name: "Robert Schneider AG", Street: "Rue du Lac 1268", ZIP: "2501", City: "Biel", Country: "CH"}
+	if p.Creditor != expectedCreditor {
+		t.Errorf("got creditor %+v, expected %+v", p.Creditor, expectedCreditor)
+	}
+	expectedDebtor := QRAddress{Name: "Pia-Maria Rutschmann-Schnyder", Street: "Grosse Marktgasse 28", ZIP: "9400", City: "Rorschach", Country: "CH"}
+	if p.Debtor != expectedDebtor {
+		t.Errorf("got debtor %+v, expected %+v", p.Debtor, expectedDebtor)
+	}
+	if p.ReferenceType != ReferenceTypeQRR || p.Reference != "210000000003139471430009017" {
+		t.Errorf("got reference %s %q", p.ReferenceType, p.Reference)
+	}
+	if p.RemittanceInfo != "Order of 15 June 2020" {
+		t.Errorf("got remittance info %q", p.RemittanceInfo)
+	}
+	if p.BillInformation != "//S1/10/10201409/11/200701/20/140.000-53/30/102673831/31/200615/32/7.7/33/7.7:139.40/40/0:30" {
+		t.Errorf("got bill information %q", p.BillInformation)
+	}
+
+	inv := p.Invoice()
+	if inv.IBAN != "CH4431999123000889012" || inv.Total == nil || *inv.Total != 1949.75 || inv.Currency != "CHF" || inv.PartnerName != "Robert Schneider AG" || inv.PartnerCountry != "CH" {
+		t.Errorf("unexpected invoice %+v", inv)
+	}
+	partner := p.Partner()
+	if partner.Street != "Rue du Lac 1268" || partner.ZIP != "2501" || partner.City != "Biel" || partner.IBAN != "CH4431999123000889012" {
+		t.Errorf("unexpected partner %+v", partner)
+	}
+}
+
+func TestParseQRPaymentSwissQRBillCombinedAddress(t *testing.T) {
+	p, err := readQRPayment(t, "qrbill-scor.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	expectedCreditor := QRAddress{Name: "Robert Schneider AG", Street: "Rue du Lac 1268", ZIP: "2501", City: "Biel", Country: "CH"}
+	if p.Creditor != expectedCreditor {
+		t.Errorf("got creditor %+v, expected %+v", p.Creditor, expectedCreditor)
+	}
+	if p.Debtor != (QRAddress{}) {
+		t.Errorf("expected no debtor but got %+v", p.Debtor)
+	}
+	if p.Amount != nil || p.Currency != money.EUR {
+		t.Errorf("got amount %v %s, expected no amount in EUR", p.Amount, p.Currency)
+	}
+	if p.ReferenceType != ReferenceTypeSCOR || p.Reference != "RF18539007547034" || p.BillInformation != "" {
+		t.Errorf("got reference %s %q and bill information %q", p.ReferenceType, p.Reference, p.BillInformation)
+	}
+
+	p, err = readQRPayment(t, "qrbill-non.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	expectedDebtor := QRAddress{Name: "Pia-Maria Rutschmann-Schnyder", Street: "Grosse Marktgasse 28", ZIP: "9400", City: "Rorschach", Country: "CH"}
+	if p.Debtor != expectedDebtor {
+		t.Errorf("got debtor %+v, expected %+v", p.Debtor, expectedDebtor)
+	}
+	if p.ReferenceType != ReferenceTypeNON || p.Reference != "" || p.RemittanceInfo != "Donation to the Winterfest campaign" {
+		t.Errorf("got reference %s %q and remittance info %q", p.ReferenceType, p.Reference, p.RemittanceInfo)
+	}
+}
+
+func TestParseQRPaymentSwissQRBillInvalid(t *testing.T) {
+	data, err := os.ReadFile("testdata/qrbill-qrr.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	valid := strings.Split(string(data), "\n")
+	for _, tt := range []struct {
+		name    string
+		line    int
+		value   string
+		wantErr string
+	}{
+		{"version", 1, "0100", "unsupported Swiss QR-bill version"},
+		{"IBAN", 3, "CH4431999123000889013", "invalid IBAN"},
+		{"IBAN country", 3, "DE89370400440532013000", "not from Switzerland"},
+		{"creditor address type", 4, "X", "creditor: invalid address type"},
+		{"creditor country", 10, "XYZ", "creditor: invalid country"},
+		{"amount", 18, "1.949,75", "invalid amount"},
+		{"currency", 19, "USD", "not CHF or EUR"},
+		{"debtor address type", 20, "X", "debtor: invalid address type"},
+		{"reference type", 27, "ABC", "invalid reference type"},
+		{"QR reference", 28, "210000000003139471430009018", "invalid QR reference"},
+		{"trailer", 30, "END", "trailer"},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			lines := append([]string(nil), valid...)
+			lines[tt.line] = tt.value
+			_, err := ParseQRPayment(strings.Join(lines, "\n"))
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("expected error with %q but got %v", tt.wantErr, err)
+			}
+		})
+	}
+	if _, err := ParseQRPayment(strings.Join(valid[:30], "\n")); err == nil {
+		t.Error("expected error for missing lines")
+	}
+}
+
+func TestParseQRPaymentEPC(t *testing.T) {
+	// Example of the EPC069-12 guidelines
+	p, err := readQRPayment(t, "epc.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p.Format != QRCodeFormatEPC || p.IBAN != "BE72000000001616" || p.BIC != "BPOTBEB1XXX" {
+		t.Errorf("got format %q, IBAN %q, BIC %q", p.Format, p.IBAN, p.BIC)
+	}
+	if p.Amount == nil || *p.Amount != 1 || p.Currency != money.EUR {
+		t.Errorf("got amount %v %s, expected 1 EUR", p.Amount, p.Currency)
+	}
+	if p.Creditor != (QRAddress{Name: "Red Cross of Belgium"}) {
+		t.Errorf("got creditor %+v", p.Creditor)
+	}
+	if p.ReferenceType != "" || p.Reference != "" || p.RemittanceInfo != "Urgency fund" {
+		t.Errorf("got reference %s %q and remittance info %q", p.ReferenceType, p.Reference, p.RemittanceInfo)
+	}
+
+	p, err = readQRPayment(t, "epc-001.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p.BIC != "COBADEFFXXX" || p.Amount == nil || *p.Amount != 1234.5 {
+		t.Errorf("got BIC %q and amount %v", p.BIC, p.Amount)
+	}
+	if p.ReferenceType != ReferenceTypeSCOR || p.Reference != "RF18539007547034" || p.RemittanceInfo != "" {
+		t.Errorf("got reference %s %q and remittance info %q", p.ReferenceType, p.Reference, p.RemittanceInfo)
+	}
+
+	// Round trip of a GiroCode payload
+	payload, err := (&GiroCode{Name: "Muster GmbH", IBAN: "DE89370400440532013000", Amount: 99.9, RemittanceInfo: "RE-1"}).Payload()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p, err = ParseQRPayment(payload); err != nil {
+		t.Fatal(err)
+	}
+	if p.IBAN != "DE89370400440532013000" || p.BIC.IsNotNull() || p.Amount == nil || *p.Amount != 99.9 || p.RemittanceInfo != "RE-1" {
+		t.Errorf("unexpected payment %+v", p)
+	}
+}
+
+func TestParseQRPaymentEPCInvalid(t *testing.T) {
+	for _, tt := range []struct {
+		name    string
+		payload string
+		wantErr string
+	}{
+		{"too short", "BCD\n002\n1\nSCT\n\nMuster GmbH", "expected at least 7"},
+		{"version", "BCD\n003\n1\nSCT\n\nMuster GmbH\nDE89370400440532013000", "unsupported EPC QR code version"},
+		{"identification", "BCD\n002\n1\nINST\n\nMuster GmbH\nDE89370400440532013000", "identification"},
+		{"BIC required for version 001", "BCD\n001\n1\nSCT\n\nMuster GmbH\nDE89370400440532013000", "BIC is required"},
+		{"IBAN", "BCD\n002\n1\nSCT\n\nMuster GmbH\nDE00370400440532013000", "invalid IBAN"},
+		{"currency", "BCD\n002\n1\nSCT\n\nMuster GmbH\nDE89370400440532013000\nCHF10", "not in EUR"},
+		{"amount", "BCD\n002\n1\nSCT\n\nMuster GmbH\nDE89370400440532013000\nEUR10,50", "invalid amount"},
+		{"reference", "BCD\n002\n1\nSCT\n\nMuster GmbH\nDE89370400440532013000\nEUR10\n\nRF19539007547034", "invalid creditor reference"},
+		{"unknown format", "XYZ\n002", "unsupported payment QR code"},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseQRPayment(tt.payload)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("expected error with %q but got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestValidQRReference(t *testing.T) {
+	for _, tt := range []struct {
+		reference string
+		want      bool
+	}{
+		{"210000000003139471430009017", true},
+		{"21 00000 00003 13947 14300 09017", true},
+		{"110001234560000000000813457", true},
+		{"000000000000000000000000000", true},
+		{"210000000003139471430009018", false},
+		{"21000000000313947143000901", false},
+		{"2100000000031394714300090170", false},
+		{"21000000000313947143000901A", false},
+		{"", false},
+	} {
+		if got := ValidQRReference(tt.reference); got != tt.want {
+			t.Errorf("ValidQRReference(%q) = %t, expected %t", tt.reference, got, tt.want)
+		}
+	}
+}
+
+func TestValidCreditorReference(t *testing.T) {
+	for _, tt := range []struct {
+		reference string
+		want      bool
+	}{
+		{"RF18539007547034", true},
+		{"RF18 5390 0754 7034", true},
+		{"rf18539007547034", true},
+		{"RF45G72UUR", true},
+		{"RF712348231", true},
+		{"RF6518K5", true},
+		{"RF19539007547034", false},
+		{"RF35C4", false},
+		{"RF00", false},
+		{"XX18539007547034", false},
+		{"RF18-5390-0754-7034", false},
+		{"RF18" + strings.Repeat("1", 22), false},
+		{"", false},
+	} {
+		if got := ValidCreditorReference(tt.reference); got != tt.want {
+			t.Errorf("ValidCreditorReference(%q) = %t, expected %t", tt.reference, got, tt.want)
+		}
+	}
+}
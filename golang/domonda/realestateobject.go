@@ -3,9 +3,12 @@ package domonda
 //go:generate go tool go-enum $GOFILE
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
 	"net/url"
 
 	"github.com/domonda/go-types/account"
@@ -13,6 +16,7 @@ import (
 	"github.com/domonda/go-types/country"
 	"github.com/domonda/go-types/notnull"
 	"github.com/domonda/go-types/nullable"
+	"github.com/domonda/go-types/uu"
 )
 
 // RealEstateObject represents a real estate property managed in the system.
@@ -20,10 +24,10 @@ import (
 // related transactions and documents. Each object is identified by its Number.
 type RealEstateObject struct {
 	// Type specifies the kind of real estate object (WEG, HI, SUB, KREIS, MANDANT, MRG, MHV, SEV)
-	Type RealEstateObjectType
+	Type RealEstateObjectType `jsonschema:"required"`
 
 	// Number is the unique identifier for this object (alphanumeric)
-	Number account.Number
+	Number account.Number `jsonschema:"required"`
 
 	// AccountingArea is an optional accounting segregation identifier
 	AccountingArea account.NullableNumber
@@ -35,7 +39,7 @@ type RealEstateObject struct {
 	Description nullable.TrimmedString
 
 	// StreetAddress is the primary street address (required)
-	StreetAddress notnull.TrimmedString
+	StreetAddress notnull.TrimmedString `jsonschema:"required"`
 
 	// AlternativeAddresses contains additional addresses for the same property
 	AlternativeAddresses nullable.StringArray
@@ -47,7 +51,7 @@ type RealEstateObject struct {
 	City nullable.TrimmedString
 
 	// Country is the ISO 3166-1 alpha-2 country code (e.g., "DE", "AT")
-	Country country.Code
+	Country country.Code `jsonschema:"required"`
 
 	// BankAccounts are payment bank accounts associated with this object
 	BankAccounts []bank.Account
@@ -87,6 +91,40 @@ func (o *RealEstateObject) Validate() error {
 	return errors.Join(errs...)
 }
 
+// Normalize normalizes the address of the object:
+// StreetAddress and AlternativeAddresses with NormalizeStreet,
+// a postal code at the beginning of City is moved to ZipCode (see SplitZIPCity),
+// and ZipCode is normalized with NormalizeZIP for the Country.
+//
+// Returns warnings of type *FieldError for values that were kept unchanged
+// like a ZipCode that doesn't match the format of the Country.
+func (o *RealEstateObject) Normalize() []error {
+	var errs []error
+	countryCode, err := o.Country.Normalized()
+	if err != nil {
+		countryCode = ""
+	}
+	o.StreetAddress = notnull.TrimmedString(NormalizeStreet(string(o.StreetAddress), countryCode))
+	for i, address := range o.AlternativeAddresses {
+		o.AlternativeAddresses[i] = NormalizeStreet(address, countryCode)
+	}
+	if o.ZipCode.IsNull() && o.City.IsNotNull() {
+		if zip, city := SplitZIPCity(o.City.String()); zip != "" {
+			o.ZipCode.Set(zip)
+			o.City.Set(city)
+		}
+	}
+	if o.ZipCode.IsNotNull() && countryCode != "" {
+		zip, err := NormalizeZIP(o.ZipCode.String(), countryCode)
+		if err != nil {
+			errs = append(errs, fieldWarning("ZipCode", err))
+		} else {
+			o.ZipCode.Set(zip)
+		}
+	}
+	return errs
+}
+
 // RealEstateObjectType categorizes real estate objects by their legal and management structure.
 // Different types have different requirements and business rules in the system.
 type RealEstateObjectType string //#enum
@@ -193,6 +231,37 @@ func (r RealEstateObjectType) IsVirtual() bool {
 	return r == RealEstateObjectTypeKREIS || r == RealEstateObjectTypeMANDANT
 }
 
+// ImportRealEstateObjectResult contains the result
+// of importing a single real estate object.
+type ImportRealEstateObjectResult struct {
+	// Number of the imported real estate object
+	Number account.Number `jsonschema:"required"`
+
+	// ID of the real estate object that was created or updated
+	ID uu.NullableID `json:",omitzero"`
+
+	// State of the object after import
+	State ImportState `jsonschema:"required"`
+
+	// Error message from the import in case of State "ERROR"
+	Error string `json:",omitempty"`
+}
+
+// ImportState implements the ImportResult interface
+func (r ImportRealEstateObjectResult) ImportState() ImportState {
+	return r.State
+}
+
+// ImportError implements the ImportResult interface
+func (r ImportRealEstateObjectResult) ImportError() string {
+	return r.Error
+}
+
+// ImportItem implements the ImportResult interface
+func (r ImportRealEstateObjectResult) ImportItem() string {
+	return r.Number.String()
+}
+
 // PostRealEstateObjects upserts (inserts or updates) real estate objects via the Domonda API.
 // Objects are identified by their Number field - if an object with the same number exists,
 // it will be updated; otherwise, a new object is created.
@@ -203,19 +272,20 @@ func (r RealEstateObjectType) IsVirtual() bool {
 //   - objects: Slice of real estate objects to import
 //   - source:  Optional identifier for the data source (e.g., your company name)
 //
-// Returns an error if validation fails or the API request fails.
+// Returns a slice of ImportRealEstateObjectResult with one result per input object
+// or an error if validation fails or the API request fails.
+// The results are nil if the server responded with an empty body.
 // The function validates all objects before sending the request.
 //
 // API endpoint: https://domonda.app/api/public/masterdata/real-estate-objects
-func PostRealEstateObjects(ctx context.Context, apiKey string, objects []*RealEstateObject, source string) error {
-	var err error
+func PostRealEstateObjects(ctx context.Context, apiKey string, objects []*RealEstateObject, source string) (results []*ImportRealEstateObjectResult, err error) {
 	for i, obj := range objects {
 		if e := obj.Validate(); e != nil {
 			err = errors.Join(err, fmt.Errorf("RealEstateObject at index %d has error: %w", i, e))
 		}
 	}
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	vals := make(url.Values)
@@ -224,10 +294,22 @@ func PostRealEstateObjects(ctx context.Context, apiKey string, objects []*RealEs
 	}
 	response, err := postJSON(ctx, apiKey, "/masterdata/real-estate-objects", vals, objects)
 	if err != nil {
-		return err
+		return nil, err
 	}
+	defer response.Body.Close()
 	if response.StatusCode != 200 {
-		return fmt.Errorf("unexpected status code: %d", response.StatusCode)
+		return nil, fmt.Errorf("unexpected status code: %d", response.StatusCode)
 	}
-	return nil
+	data, err := io.ReadAll(response.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response body: %w", err)
+	}
+	if len(bytes.TrimSpace(data)) == 0 {
+		// Successful import without results in the response
+		return nil, nil
+	}
+	if err := json.Unmarshal(data, &results); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal response body: %w", err)
+	}
+	return results, nil
 }
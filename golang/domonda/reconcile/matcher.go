@@ -0,0 +1,474 @@
+package reconcile
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"strings"
+	"unicode"
+
+	"github.com/domonda/go-types/date"
+	"github.com/domonda/go-types/money"
+
+	"github.com/domonda/api/golang/domonda"
+	"github.com/domonda/api/golang/domonda/bankstatement"
+	"github.com/domonda/api/golang/domonda/partnername"
+)
+
+// Matcher matches invoices to the bank transactions paying them
+type Matcher struct {
+	// MinConfidence of the returned matches from 0 to 1
+	MinConfidence float64
+
+	// MinNameSimilarity of the partner names of an invoice
+	// and a transaction from 0 to 1 to count as same partner
+	MinNameSimilarity float64
+
+	// PaymentType is the type of the transactions paying invoices
+	// like OUTGOING for incoming invoices from vendors.
+	// Credit memos are paid by transactions of the other type.
+	// Transactions of both types are matched if empty.
+	PaymentType bankstatement.TransactionType
+
+	// DiscountGraceDays are the days after DiscountUntil
+	// within which a discount deduction is still accepted
+	// to allow for the transfer time of the payment
+	DiscountGraceDays int
+
+	// MaxCombinedInvoices is the maximum number of invoices
+	// of a partner combined to find a transaction paying multiple invoices
+	MaxCombinedInvoices int
+}
+
+// NewMatcher returns a Matcher with default settings
+func NewMatcher() *Matcher {
+	return &Matcher{
+		MinConfidence:       0.5,
+		MinNameSimilarity:   0.6,
+		DiscountGraceDays:   3,
+		MaxCombinedInvoices: 8,
+	}
+}
+
+// MatchInvoices matches invoices to transactions using NewMatcher.
+// See Matcher.Match
+func MatchInvoices(invoices []*domonda.Invoice, transactions []*bankstatement.Transaction) *Result {
+	return NewMatcher().Match(invoices, transactions)
+}
+
+// candidate is a possible match before assignment
+type candidate struct {
+	index int // of the transaction for stable sorting
+	match *Match
+}
+
+// Match returns the matches of invoices and transactions
+// with a confidence of at least MinConfidence.
+//
+// Invoices are only matched to transactions with evidence
+// besides the amount like the invoice number in the purpose
+// or the IBAN or name of the partner. Partial payments
+// require the invoice number in the purpose.
+//
+// Every transaction is matched at most once and an invoice
+// can be paid by one full or discounted payment
+// or by multiple partial payments up to its Total.
+// Invoices without Total and pending transactions are ignored.
+// The invoices and transactions are not modified.
+func (m *Matcher) Match(invoices []*domonda.Invoice, transactions []*bankstatement.Transaction) *Result {
+	open := make(map[*domonda.Invoice]money.Amount, len(invoices))
+	for _, inv := range invoices {
+		if inv != nil && inv.Total != nil && *inv.Total > 0 {
+			open[inv] = *inv.Total
+		}
+	}
+	var candidates []candidate
+	for i, t := range transactions {
+		if t == nil || t.Pending || !t.Amount.ValidAndGreaterZero() {
+			continue
+		}
+		text := newPurposeText(strings.Join([]string{t.Purpose, t.Title, t.Reference, t.EndToEndID}, " "))
+		var (
+			related  []*evidence
+			matching int
+		)
+		for _, inv := range invoices {
+			if _, ok := open[inv]; !ok || !m.compatible(t, inv) {
+				continue
+			}
+			if amountMatches(t.Amount, *inv.Total) || (inv.Discount() != 0 && amountMatches(t.Amount, *inv.Total-inv.Discount())) {
+				matching++
+			}
+			// Without evidence besides the amount any
+			// transaction with the same amount would match
+			if e := m.evidence(t, text, inv); e.score > 0 {
+				related = append(related, e)
+			}
+		}
+		for _, e := range related {
+			if match := m.single(t, e, matching == 1); match != nil {
+				candidates = append(candidates, candidate{index: i, match: match})
+			}
+		}
+		if match := m.combined(t, related); match != nil {
+			candidates = append(candidates, candidate{index: i, match: match})
+		}
+	}
+
+	slices.SortStableFunc(candidates, func(a, b candidate) int {
+		if c := cmp.Compare(b.match.Confidence, a.match.Confidence); c != 0 {
+			return c
+		}
+		return cmp.Compare(a.index, b.index)
+	})
+	result := new(Result)
+	matched := make(map[*bankstatement.Transaction]bool)
+	for _, c := range candidates {
+		if c.match.Confidence < m.MinConfidence || matched[c.match.Transaction] || !applicable(c.match, open) {
+			continue
+		}
+		for _, p := range c.match.Payments {
+			open[p.Invoice] = (open[p.Invoice] - p.Amount - p.Discount).RoundToCents()
+			p.Open = open[p.Invoice]
+		}
+		matched[c.match.Transaction] = true
+		result.Matches = append(result.Matches, c.match)
+	}
+	for _, t := range transactions {
+		if t != nil && !matched[t] {
+			result.UnmatchedTransactions = append(result.UnmatchedTransactions, t)
+		}
+	}
+	for _, inv := range invoices {
+		if amount, ok := open[inv]; ok && amount >= 0.01 {
+			result.OpenInvoices = append(result.OpenInvoices, inv)
+		}
+	}
+	return result
+}
+
+// compatible returns if the currencies and the direction
+// of the transaction and the invoice don't contradict
+func (m *Matcher) compatible(t *bankstatement.Transaction, inv *domonda.Invoice) bool {
+	if inv.Currency.IsNotNull() && t.Currency != "" && inv.Currency.Get() != t.Currency {
+		return false
+	}
+	if m.PaymentType == "" {
+		return true
+	}
+	paymentType := m.PaymentType
+	if inv.CreditMemo != nil && *inv.CreditMemo {
+		paymentType = bankstatement.TransactionTypeIncoming
+		if m.PaymentType == bankstatement.TransactionTypeIncoming {
+			paymentType = bankstatement.TransactionTypeOutgoing
+		}
+	}
+	return t.Type == paymentType
+}
+
+// evidence that a transaction is related to an invoice
+// independent of the amount
+type evidence struct {
+	invoice *domonda.Invoice
+	score   float64 // from 0 to 1
+	number  bool    // invoice number in purpose
+	reasons []string
+}
+
+func (m *Matcher) evidence(t *bankstatement.Transaction, text purposeText, inv *domonda.Invoice) *evidence {
+	e := &evidence{invoice: inv}
+	if text.containsNumber(compact(inv.InvoiceNumber.String())) {
+		e.score += 0.5
+		e.number = true
+		e.reasons = append(e.reasons, "invoice number in purpose")
+	}
+	if inv.IBAN.IsNotNull() && t.PartnerIBAN.IsNotNull() && ibanKey(string(inv.IBAN)) == ibanKey(string(t.PartnerIBAN)) {
+		e.score += 0.3
+		e.reasons = append(e.reasons, "partner IBAN")
+	}
+	if inv.PartnerName.IsNotNull() && t.PartnerName != "" {
+		if similarity := partnername.Similarity(inv.PartnerName.String(), t.PartnerName); similarity >= m.MinNameSimilarity {
+			e.score += 0.2 * similarity
+			e.reasons = append(e.reasons, fmt.Sprintf("partner name similarity %.2f", similarity))
+		}
+	}
+	return e
+}
+
+// single returns the candidate match of a transaction
+// paying the invoice of e or nil if the amount doesn't fit
+func (m *Matcher) single(t *bankstatement.Transaction, e *evidence, uniqueAmount bool) *Match {
+	inv := e.invoice
+	var (
+		payment = &Payment{Invoice: inv, Amount: t.Amount}
+		amount  float64
+		reasons = slices.Clone(e.reasons)
+	)
+	discount := inv.Discount()
+	switch {
+	case amountMatches(t.Amount, *inv.Total):
+		payment.Type = PaymentTypeFull
+		amount = 1
+		reasons = append(reasons, "total amount")
+	case discount != 0 && amountMatches(t.Amount, *inv.Total-discount):
+		payment.Type = PaymentTypeDiscount
+		payment.Discount = discount
+		if m.inDiscountPeriod(t.BookingDate, inv) {
+			amount = 1
+			reasons = append(reasons, "total amount minus discount")
+		} else {
+			amount = 0.6
+			reasons = append(reasons, "total amount minus discount after discount period")
+		}
+	case t.Amount < *inv.Total && e.number:
+		// Scores at least 0.54 with only the invoice number
+		payment.Type = PaymentTypePartial
+		amount = 0.6
+		reasons = append(reasons, "partial amount")
+	default:
+		return nil
+	}
+	confidence := 0.6*e.score + 0.4*amount
+	if uniqueAmount && payment.Type != PaymentTypePartial {
+		confidence += 0.1
+		reasons = append(reasons, "unique amount")
+	}
+	confidence *= dateFactor(t, inv, &reasons)
+	return &Match{
+		Transaction: t,
+		Payments:    []*Payment{payment},
+		Confidence:  min(confidence, 1),
+		Reasons:     reasons,
+	}
+}
+
+// combined returns the candidate match of a transaction paying
+// multiple related invoices with the sum of their totals
+// or discounted totals, or nil if no combination fits
+func (m *Matcher) combined(t *bankstatement.Transaction, related []*evidence) *Match {
+	if len(related) < 2 {
+		return nil
+	}
+	related = slices.Clone(related)
+	slices.SortStableFunc(related, func(a, b *evidence) int { return cmp.Compare(b.score, a.score) })
+	if len(related) > m.MaxCombinedInvoices {
+		related = related[:m.MaxCombinedInvoices]
+	}
+	// Options of every invoice: not paid, total, or discounted total
+	options := make([][]int64, len(related))
+	for i, e := range related {
+		options[i] = []int64{0, e.invoice.Total.Cents()}
+		if discount := e.invoice.Discount(); discount != 0 && m.inDiscountPeriod(t.BookingDate, e.invoice) {
+			options[i] = append(options[i], (*e.invoice.Total - discount).Cents())
+		}
+	}
+	var (
+		target    = t.Amount.Cents()
+		choice    = make([]int, len(related))
+		best      []int
+		bestScore float64
+	)
+	var search func(i int, sum int64, count int, score float64)
+	search = func(i int, sum int64, count int, score float64) {
+		if sum > target {
+			return
+		}
+		if i == len(related) {
+			if sum == target && count >= 2 && score/float64(count) > bestScore {
+				best, bestScore = slices.Clone(choice), score/float64(count)
+			}
+			return
+		}
+		for option, cents := range options[i] {
+			choice[i] = option
+			if option == 0 {
+				search(i+1, sum, count, score)
+			} else {
+				search(i+1, sum+cents, count+1, score+related[i].score)
+			}
+		}
+	}
+	search(0, 0, 0, 0)
+	if best == nil {
+		return nil
+	}
+	match := &Match{Transaction: t, Reasons: []string{"sum of invoice totals"}}
+	factor := 1.0
+	for i, option := range best {
+		if option == 0 {
+			continue
+		}
+		e := related[i]
+		payment := &Payment{Invoice: e.invoice, Type: PaymentTypeFull, Amount: *e.invoice.Total}
+		if option == 2 {
+			payment.Type = PaymentTypeDiscount
+			payment.Discount = e.invoice.Discount()
+			payment.Amount = (*e.invoice.Total - payment.Discount).RoundToCents()
+		}
+		match.Payments = append(match.Payments, payment)
+		for _, reason := range e.reasons {
+			if !slices.Contains(match.Reasons, reason) {
+				match.Reasons = append(match.Reasons, reason)
+			}
+		}
+		factor = min(factor, dateFactor(t, e.invoice, &match.Reasons))
+	}
+	match.Confidence = min((0.6*bestScore+0.4)*factor, 1)
+	return match
+}
+
+// inDiscountPeriod returns if the booking date is not
+// later than DiscountGraceDays after DiscountUntil of the invoice
+func (m *Matcher) inDiscountPeriod(bookingDate date.Date, inv *domonda.Invoice) bool {
+	return inv.DiscountUntil.IsNull() || !bookingDate.After(inv.DiscountUntil.Get().AddDays(m.DiscountGraceDays))
+}
+
+// applicable returns if the invoices of the match
+// are still open for its payments
+func applicable(match *Match, open map[*domonda.Invoice]money.Amount) bool {
+	for _, p := range match.Payments {
+		switch p.Type {
+		case PaymentTypeFull, PaymentTypeDiscount:
+			if !open[p.Invoice].WithinOneCent(*p.Invoice.Total) {
+				return false
+			}
+		case PaymentTypePartial:
+			if p.Amount > open[p.Invoice]+0.005 {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// dateFactor returns the factor for the confidence
+// which is reduced if the transaction was booked
+// before the invoice date
+func dateFactor(t *bankstatement.Transaction, inv *domonda.Invoice, reasons *[]string) float64 {
+	if inv.InvoiceDate.IsNotNull() && t.BookingDate.Before(inv.InvoiceDate.Get()) {
+		if reason := "booked before invoice date"; !slices.Contains(*reasons, reason) {
+			*reasons = append(*reasons, reason)
+		}
+		return 0.7
+	}
+	return 1
+}
+
+func amountMatches(a, b money.Amount) bool {
+	return a.RoundToCents().WithinOneCent(b.RoundToCents())
+}
+
+// compact returns s in upper case with only letters and digits
+func compact(s string) string {
+	return strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			return unicode.ToUpper(r)
+		}
+		return -1
+	}, s)
+}
+
+// purposeText are the runs of the compacted text of a transaction
+// that can contain an invoice number.
+// Tokens are joined to a run if they are only separated by
+// "-" or "/", or by whitespace that is not between two digits,
+// so numbers are never joined across ",", "." or whitespace
+// like in amounts or dates.
+type purposeText []purposeRun
+
+// purposeRun is a compacted run of tokens of a purposeText
+type purposeRun struct {
+	compact string
+	// separated contains the byte offsets of compact
+	// where separators were removed, the start and end included
+	separated map[int]bool
+}
+
+// newPurposeText returns the runs of s compacted like compact
+func newPurposeText(s string) purposeText {
+	var (
+		text      purposeText
+		b         strings.Builder
+		separated = map[int]bool{0: true}
+		pending   []rune // separators since the last letter or digit
+		lastDigit bool
+	)
+	flush := func() {
+		if b.Len() > 0 {
+			separated[b.Len()] = true
+			text = append(text, purposeRun{compact: b.String(), separated: separated})
+		}
+		b.Reset()
+		separated = map[int]bool{0: true}
+	}
+	for _, r := range s {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			pending = append(pending, r)
+			continue
+		}
+		if len(pending) > 0 {
+			if joinsTokens(pending, lastDigit && unicode.IsDigit(r)) {
+				separated[b.Len()] = true
+			} else {
+				flush()
+			}
+			pending = pending[:0]
+		}
+		b.WriteRune(unicode.ToUpper(r))
+		lastDigit = unicode.IsDigit(r)
+	}
+	flush()
+	return text
+}
+
+// joinsTokens returns if the separators join two tokens of a run
+func joinsTokens(separators []rune, betweenDigits bool) bool {
+	for _, r := range separators {
+		switch {
+		case r == '-' || r == '/':
+		case unicode.IsSpace(r) && !betweenDigits:
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// containsNumber returns if a run of the text contains the compacted
+// invoice number of at least 3 characters beginning and ending
+// at a separator or at a change between letters and digits,
+// so "4711" is found in "RE 4711, 200,00 EUR" and "RE4711"
+// but not in "RE 47110" or "47 11", and "1190" not in "11,90 EUR".
+func (t purposeText) containsNumber(number string) bool {
+	if len(number) < 3 {
+		return false
+	}
+	for _, run := range t {
+		if run.containsNumber(number) {
+			return true
+		}
+	}
+	return false
+}
+
+func (r *purposeRun) containsNumber(number string) bool {
+	isDigit := func(b byte) bool { return b >= '0' && b <= '9' }
+	boundary := func(i int) bool {
+		return r.separated[i] || isDigit(r.compact[i-1]) != isDigit(r.compact[i])
+	}
+	for offset := 0; ; {
+		i := strings.Index(r.compact[offset:], number)
+		if i < 0 {
+			return false
+		}
+		start, end := offset+i, offset+i+len(number)
+		if boundary(start) && boundary(end) {
+			return true
+		}
+		offset = start + 1
+	}
+}
+
+func ibanKey(iban string) string {
+	return strings.ToUpper(strings.ReplaceAll(iban, " ", ""))
+}
@@ -0,0 +1,204 @@
+package reconcile
+
+import (
+	"testing"
+
+	"github.com/domonda/go-types/bank"
+	"github.com/domonda/go-types/date"
+	"github.com/domonda/go-types/money"
+	"github.com/domonda/go-types/nullable"
+
+	"github.com/domonda/api/golang/domonda"
+	"github.com/domonda/api/golang/domonda/bankstatement"
+)
+
+const acmeIBAN = "AT611904300234573201"
+
+func testInvoice(number string, total money.Amount) *domonda.Invoice {
+	return &domonda.Invoice{
+		PartnerName:   nullable.TrimmedString("ACME GmbH"),
+		InvoiceNumber: nullable.TrimmedString(number),
+		InvoiceDate:   "2024-03-01",
+		Total:         total.Ptr(),
+		Currency:      money.NullableCurrency(money.EUR),
+		IBAN:          acmeIBAN,
+	}
+}
+
+func testTransaction(amount money.Amount, booked date.Date, purpose string) *bankstatement.Transaction {
+	return &bankstatement.Transaction{
+		Type:        bankstatement.TransactionTypeOutgoing,
+		Amount:      amount,
+		Currency:    money.EUR,
+		BookingDate: booked,
+		Purpose:     purpose,
+	}
+}
+
+func TestMatchDiscount(t *testing.T) {
+	inv := testInvoice("RE-1001", 1190)
+	inv.DiscountPercent = money.Rate(2).Ptr()
+	inv.DiscountUntil = "2024-03-10"
+	tx := testTransaction(1166.20, "2024-03-12", "Rechnung RE-1001 abzgl. 2% Skonto")
+	tx.PartnerName = "ACME GmbH"
+
+	matcher := NewMatcher()
+	matcher.PaymentType = bankstatement.TransactionTypeOutgoing
+	result := matcher.Match([]*domonda.Invoice{inv}, []*bankstatement.Transaction{tx})
+	if len(result.Matches) != 1 {
+		t.Fatalf("got %d matches, expected 1", len(result.Matches))
+	}
+	match := result.Matches[0]
+	if len(match.Payments) != 1 {
+		t.Fatalf("got %d payments, expected 1", len(match.Payments))
+	}
+	p := match.Payments[0]
+	if p.Type != PaymentTypeDiscount || p.Discount != 23.80 || p.Open != 0 {
+		t.Errorf("got %s payment with discount %s and open %s, expected DISCOUNT 23.80 and 0.00", p.Type, p.Discount, p.Open)
+	}
+	if match.Confidence < 0.9 {
+		t.Errorf("got confidence %.2f within discount grace days, expected at least 0.9", match.Confidence)
+	}
+	if len(result.OpenInvoices) != 0 || len(result.UnmatchedTransactions) != 0 {
+		t.Errorf("got %d open invoices and %d unmatched transactions", len(result.OpenInvoices), len(result.UnmatchedTransactions))
+	}
+
+	// After the grace days the discount deduction is less likely
+	late := testTransaction(1166.20, "2024-03-20", "Rechnung RE-1001")
+	result = matcher.Match([]*domonda.Invoice{inv}, []*bankstatement.Transaction{late})
+	if len(result.Matches) != 1 || result.Matches[0].Confidence >= match.Confidence {
+		t.Errorf("expected one match with less confidence than %.2f, got %v", match.Confidence, result.Matches)
+	}
+}
+
+func TestMatchPartial(t *testing.T) {
+	inv := testInvoice("4711", 500)
+	// Only the invoice number as evidence
+	first := testTransaction(200, "2024-03-05", "RE 4711, 200,00 EUR")
+	second := testTransaction(300, "2024-03-20", "Rest RE 4711")
+
+	result := MatchInvoices([]*domonda.Invoice{inv}, []*bankstatement.Transaction{first, second})
+	if len(result.Matches) != 2 {
+		t.Fatalf("got %d matches, expected 2: %v", len(result.Matches), result.Matches)
+	}
+	open := map[*bankstatement.Transaction]money.Amount{first: 300, second: 0}
+	for _, match := range result.Matches {
+		p := match.Payments[0]
+		if p.Type != PaymentTypePartial || p.Open != open[match.Transaction] {
+			t.Errorf("got %s payment of %s with open %s, expected PARTIAL with open %s", p.Type, p.Amount, p.Open, open[match.Transaction])
+		}
+		if match.Confidence < 0.5 {
+			t.Errorf("got confidence %.2f below default MinConfidence", match.Confidence)
+		}
+	}
+	if len(result.OpenInvoices) != 0 {
+		t.Errorf("got %d open invoices, expected 0", len(result.OpenInvoices))
+	}
+
+	// A smaller amount without the invoice number is no partial payment
+	other := testTransaction(200, "2024-03-05", "Anzahlung")
+	other.PartnerIBAN = acmeIBAN
+	result = MatchInvoices([]*domonda.Invoice{inv}, []*bankstatement.Transaction{other})
+	if len(result.Matches) != 0 {
+		t.Errorf("expected no match, got %v", result.Matches)
+	}
+}
+
+func TestMatchAmountInPurpose(t *testing.T) {
+	// The invoice number must not match digits of the amount
+	inv := testInvoice("1190", 500)
+	tx := testTransaction(200, "2024-03-05", "Miete 11,90 EUR")
+	result := MatchInvoices([]*domonda.Invoice{inv}, []*bankstatement.Transaction{tx})
+	if len(result.Matches) != 0 {
+		t.Errorf("expected no match, got %s", result.Matches[0])
+	}
+}
+
+func TestMatchMultipleInvoices(t *testing.T) {
+	invoices := []*domonda.Invoice{
+		testInvoice("RE-2001", 100),
+		testInvoice("RE-2002", 250),
+		testInvoice("RE-2003", 75),
+	}
+	tx := testTransaction(350, "2024-03-15", "RE-2001 RE-2002")
+	tx.PartnerIBAN = bank.NullableIBAN(acmeIBAN)
+
+	result := MatchInvoices(invoices, []*bankstatement.Transaction{tx})
+	if len(result.Matches) != 1 {
+		t.Fatalf("got %d matches, expected 1", len(result.Matches))
+	}
+	match := result.Matches[0]
+	if len(match.Payments) != 2 {
+		t.Fatalf("got %d payments, expected 2: %s", len(match.Payments), match)
+	}
+	for i, p := range match.Payments {
+		if p.Invoice != invoices[i] || p.Type != PaymentTypeFull || p.Amount != *invoices[i].Total {
+			t.Errorf("payment %d: got %s %s of invoice %s", i, p.Type, p.Amount, p.Invoice.InvoiceNumber)
+		}
+	}
+	if len(result.OpenInvoices) != 1 || result.OpenInvoices[0] != invoices[2] {
+		t.Errorf("expected only RE-2003 open, got %d open invoices", len(result.OpenInvoices))
+	}
+}
+
+func TestMatchNegative(t *testing.T) {
+	inv := testInvoice("RE-3001", 119)
+	salary := &bankstatement.Transaction{
+		Type:        bankstatement.TransactionTypeIncoming,
+		Amount:      119,
+		Currency:    money.EUR,
+		BookingDate: "2024-03-28",
+		PartnerName: "Max Mustermann",
+		Purpose:     "Gehalt Maerz",
+	}
+	for name, tx := range map[string]*bankstatement.Transaction{
+		"only amount":      salary,
+		"other currency":   {Type: bankstatement.TransactionTypeOutgoing, Amount: 119, Currency: money.CHF, BookingDate: "2024-03-05", Purpose: "RE-3001"},
+		"other number":     testTransaction(119, "2024-03-05", "RE-30011"),
+		"higher amount":    testTransaction(120, "2024-03-05", "RE-3001"),
+		"pending":          {Type: bankstatement.TransactionTypeOutgoing, Amount: 119, Currency: money.EUR, BookingDate: "2024-03-05", Purpose: "RE-3001", Pending: true},
+		"not payment type": {Type: bankstatement.TransactionTypeIncoming, Amount: 119, Currency: money.EUR, BookingDate: "2024-03-05", Purpose: "RE-3001"},
+	} {
+		t.Run(name, func(t *testing.T) {
+			matcher := NewMatcher()
+			if name == "not payment type" {
+				matcher.PaymentType = bankstatement.TransactionTypeOutgoing
+			}
+			result := matcher.Match([]*domonda.Invoice{inv}, []*bankstatement.Transaction{tx})
+			if len(result.Matches) != 0 {
+				t.Errorf("expected no match, got %s", result.Matches[0])
+			}
+			if len(result.OpenInvoices) != 1 || len(result.UnmatchedTransactions) != 1 {
+				t.Errorf("got %d open invoices and %d unmatched transactions, expected 1 and 1", len(result.OpenInvoices), len(result.UnmatchedTransactions))
+			}
+		})
+	}
+}
+
+func TestContainsNumber(t *testing.T) {
+	for _, tt := range []struct {
+		text   string
+		number string
+		want   bool
+	}{
+		{"RE 4711, 200,00 EUR", "4711", true},
+		{"Rechnung 4711 vom 01.03.2024", "4711", true},
+		{"RE4711", "4711", true},
+		{"RE-2024-17", "RE 2024/17", true},
+		{"Rechnung RE 2024/17 danke", "RE-2024-17", true},
+		{"re 2024 17 danke", "RE-2024-17", false},
+		{"Miete 11,90 EUR", "1190", false},
+		{"RE 4711, 200,00 EUR", "20000", false},
+		{"vom 01.03.2024", "0103", false},
+		{"XRE4711", "RE4711", false},
+		{"47 11", "4711", false},
+		{"RE 47110", "4711", false},
+		{"Kunde 1234711", "4711", false},
+		{"RE 4711", "47", false},
+		{"", "4711", false},
+	} {
+		if got := newPurposeText(tt.text).containsNumber(compact(tt.number)); got != tt.want {
+			t.Errorf("containsNumber(%q, %q) = %t, expected %t", tt.text, tt.number, got, tt.want)
+		}
+	}
+}
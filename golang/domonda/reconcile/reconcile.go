@@ -0,0 +1,155 @@
+// Package reconcile matches invoices to the bank transactions paying them
+// and proposes matches with confidence scores like the
+// DocumentBankTransaction type of the GraphQL API.
+//
+// Matches are found by the invoice number in the purpose of a transaction,
+// the IBAN and name of the partner, and the amount including
+// partial payments, early payment discounts (Skonto),
+// and transfers paying multiple invoices.
+package reconcile
+
+//go:generate go tool go-enum $GOFILE
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/domonda/go-types/money"
+
+	"github.com/domonda/api/golang/domonda"
+	"github.com/domonda/api/golang/domonda/bankstatement"
+)
+
+// PaymentType is the kind of payment of an invoice by a transaction
+type PaymentType string //#enum
+
+const (
+	// PaymentTypeFull pays the Total of the invoice
+	PaymentTypeFull PaymentType = "FULL"
+
+	// PaymentTypeDiscount pays the Total minus the discount of the invoice
+	PaymentTypeDiscount PaymentType = "DISCOUNT"
+
+	// PaymentTypePartial pays a part of the open amount of the invoice
+	PaymentTypePartial PaymentType = "PARTIAL"
+)
+
+// Valid indicates if p is any of the valid values for PaymentType
+func (p PaymentType) Valid() bool {
+	switch p {
+	case
+		PaymentTypeFull,
+		PaymentTypeDiscount,
+		PaymentTypePartial:
+		return true
+	}
+	return false
+}
+
+// Validate returns an error if p is none of the valid values for PaymentType
+func (p PaymentType) Validate() error {
+	if !p.Valid() {
+		return fmt.Errorf("invalid value %#v for type reconcile.PaymentType", p)
+	}
+	return nil
+}
+
+// Enums returns all valid values for PaymentType
+func (PaymentType) Enums() []PaymentType {
+	return []PaymentType{
+		PaymentTypeFull,
+		PaymentTypeDiscount,
+		PaymentTypePartial,
+	}
+}
+
+// EnumStrings returns all valid values for PaymentType as strings
+func (PaymentType) EnumStrings() []string {
+	return []string{
+		"FULL",
+		"DISCOUNT",
+		"PARTIAL",
+	}
+}
+
+// String implements the fmt.Stringer interface for PaymentType
+func (p PaymentType) String() string {
+	return string(p)
+}
+
+// Payment of an invoice by the transaction of a Match
+type Payment struct {
+	// Invoice that is paid
+	Invoice *domonda.Invoice
+
+	// Type of the payment
+	Type PaymentType
+
+	// Amount of the transaction paying the invoice
+	Amount money.Amount
+
+	// Discount deducted from the Total for PaymentTypeDiscount
+	Discount money.Amount
+
+	// Open amount of the invoice after the payment
+	Open money.Amount
+}
+
+// Match is a transaction paying one or more invoices
+type Match struct {
+	// Transaction paying the invoices
+	Transaction *bankstatement.Transaction
+
+	// Payments of the invoices, more than one
+	// if the transaction pays multiple invoices
+	Payments []*Payment
+
+	// Confidence of the match from 0 to 1
+	Confidence float64
+
+	// Reasons for the match like "invoice number in purpose"
+	Reasons []string
+}
+
+// String implements the fmt.Stringer interface for Match
+func (m *Match) String() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s %s %s %s %q:", m.Transaction.BookingDate, m.Transaction.Type, m.Transaction.Amount, m.Transaction.Currency, m.Transaction.PartnerName)
+	for _, p := range m.Payments {
+		fmt.Fprintf(&b, " %s invoice %q %s", p.Type, p.Invoice.InvoiceNumber.String(), p.Amount)
+		if p.Discount != 0 {
+			fmt.Fprintf(&b, " discount %s", p.Discount)
+		}
+		if p.Open != 0 {
+			fmt.Fprintf(&b, " open %s", p.Open)
+		}
+	}
+	fmt.Fprintf(&b, " (confidence %.2f: %s)", m.Confidence, strings.Join(m.Reasons, ", "))
+	return b.String()
+}
+
+// Result of matching invoices and transactions
+type Result struct {
+	// Matches sorted by descending confidence
+	Matches []*Match
+
+	// UnmatchedTransactions are the transactions without Match
+	UnmatchedTransactions []*bankstatement.Transaction
+
+	// OpenInvoices are the invoices that are not or only partially paid
+	OpenInvoices []*domonda.Invoice
+}
+
+// MatchesOf returns the matches paying the invoice
+func (r *Result) MatchesOf(invoice *domonda.Invoice) []*Match {
+	var matches []*Match
+	for _, m := range r.Matches {
+		for _, p := range m.Payments {
+			if p.Invoice == invoice {
+				matches = append(matches, m)
+				break
+			}
+		}
+	}
+	return matches
+}
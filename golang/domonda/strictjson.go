@@ -0,0 +1,144 @@
+package domonda
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+)
+
+// StrictJSONReport contains the findings of strictly decoding
+// a JSON array of master data items.
+//
+// The API server uses encoding/json which matches object member names
+// case-insensitive and silently ignores unknown members,
+// so a typo like "VatIdNo" instead of "VATIDNo" drops the value
+// without any error.
+type StrictJSONReport struct {
+	// UnknownFields are object members that match no field
+	// of the decoded type, not even case-insensitive.
+	// They are also returned as errors by the decode functions.
+	UnknownFields []JSONMember
+
+	// CaseMismatches are object members that only match
+	// a field name case-insensitive.
+	// They are accepted by the API but should be fixed.
+	CaseMismatches []JSONMember
+
+	// AbsentFields are optional fields that had no member in their object
+	// and will be decoded as null or empty values.
+	AbsentFields []JSONMember
+
+	// MissingFields are required fields that had no member in their object.
+	// They are also returned as errors by the decode functions.
+	MissingFields []JSONMember
+}
+
+// HasWarnings returns true if the report
+// contains any case mismatches or absent optional fields.
+func (r *StrictJSONReport) HasWarnings() bool {
+	return len(r.CaseMismatches) > 0 || len(r.AbsentFields) > 0
+}
+
+// Warnings returns human readable warnings
+// for the case mismatches and absent optional fields.
+func (r *StrictJSONReport) Warnings() []string {
+	var warnings []string
+	for _, m := range r.CaseMismatches {
+		warnings = append(warnings, fmt.Sprintf("%s: field %q has case mismatch with its Go struct field", m.Position(), m.Path))
+	}
+	for _, m := range r.AbsentFields {
+		warnings = append(warnings, fmt.Sprintf("%s: optional field %q is absent", m.Position(), m.Path))
+	}
+	return warnings
+}
+
+// DecodePartnersStrict decodes a JSON array of partners
+// as posted by PostPartners and validates every partner.
+// Unknown object members and missing required fields
+// are returned as errors with their line and column.
+// Case-mismatched member names and absent optional fields
+// are listed in the returned report.
+//
+// The decoded partners are also returned if the error
+// is only about unknown members, missing fields or invalid data.
+func DecodePartnersStrict(r io.Reader) ([]*Partner, *StrictJSONReport, error) {
+	return decodeStrict(r, "Partner", (*Partner).Validate)
+}
+
+// DecodeGLAccountsStrict decodes a JSON array of general ledger accounts
+// as posted by PostGLAccounts and validates every account.
+// See DecodePartnersStrict for details about the returned report and error.
+func DecodeGLAccountsStrict(r io.Reader) ([]*GLAccount, *StrictJSONReport, error) {
+	return decodeStrict(r, "GLAccount", (*GLAccount).Validate)
+}
+
+// DecodeBankAccountsStrict decodes a JSON array of bank accounts
+// as posted by PostBankAccounts and validates every account.
+// See DecodePartnersStrict for details about the returned report and error.
+func DecodeBankAccountsStrict(r io.Reader) ([]*BankAccount, *StrictJSONReport, error) {
+	return decodeStrict(r, "BankAccount", (*BankAccount).Validate)
+}
+
+// DecodeRealEstateObjectsStrict decodes a JSON array of real estate objects
+// as posted by PostRealEstateObjects and validates every object.
+// See DecodePartnersStrict for details about the returned report and error.
+func DecodeRealEstateObjectsStrict(r io.Reader) ([]*RealEstateObject, *StrictJSONReport, error) {
+	return decodeStrict(r, "RealEstateObject", (*RealEstateObject).Validate)
+}
+
+// DecodeObjectTenantOwnersStrict decodes a JSON array of object tenant owners
+// as posted by PostObjectTenantOwners and validates every tenant owner.
+// See DecodePartnersStrict for details about the returned report and error.
+func DecodeObjectTenantOwnersStrict(r io.Reader) ([]*ObjectTenantOwner, *StrictJSONReport, error) {
+	return decodeStrict(r, "ObjectTenantOwner", (*ObjectTenantOwner).Validate)
+}
+
+func decodeStrict[T any](r io.Reader, typeName string, validate func(*T) error) (items []*T, report *StrictJSONReport, err error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	report = new(StrictJSONReport)
+	err = walkJSONFields(data, reflect.TypeFor[[]*T](), jsonFieldVisitor{
+		unknown: func(member JSONMember, _ string) {
+			report.UnknownFields = append(report.UnknownFields, member)
+		},
+		caseMismatch: func(member JSONMember, _ string) {
+			report.CaseMismatches = append(report.CaseMismatches, member)
+		},
+		absent: func(member JSONMember, field jsonStructField) {
+			if field.Optional {
+				report.AbsentFields = append(report.AbsentFields, member)
+			} else {
+				report.MissingFields = append(report.MissingFields, member)
+			}
+		},
+	})
+	if err != nil {
+		return nil, report, err
+	}
+	if err = json.Unmarshal(data, &items); err != nil {
+		return nil, report, jsonErrorWithPosition(data, err)
+	}
+
+	var errs []error
+	for _, m := range report.UnknownFields {
+		errs = append(errs, fmt.Errorf("%s: unknown field %q", m.Position(), m.Path))
+	}
+	for _, m := range report.MissingFields {
+		errs = append(errs, fmt.Errorf("%s: missing required field %q", m.Position(), m.Path))
+	}
+	for i, item := range items {
+		if item == nil {
+			errs = append(errs, fmt.Errorf("%s at index %d is null", typeName, i))
+			continue
+		}
+		if e := validate(item); e != nil {
+			errs = append(errs, fmt.Errorf("%s at index %d has error: %w", typeName, i, e))
+		}
+	}
+	return items, report, errors.Join(errs...)
+}
@@ -0,0 +1,232 @@
+package domonda
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// RowError is an error of a data row
+// of a CSV file or spreadsheet.
+type RowError struct {
+	// Line is the 1-based line or row number in the file
+	Line int
+
+	// Err is the error of the row
+	Err error
+}
+
+func (e *RowError) Error() string {
+	return fmt.Sprintf("line %d: %s", e.Line, e.Err)
+}
+
+func (e *RowError) Unwrap() error {
+	return e.Err
+}
+
+// tableRow is a data row of a CSV file or spreadsheet
+type tableRow struct {
+	// Line is the 1-based line or row number in the file
+	Line   int
+	Values []string
+}
+
+// normalizeColumnName returns name in lower case without whitespace
+// and punctuation and with German umlauts replaced by their
+// two letter forms, so that "USt-IdNr." and "Straße"
+// match "ustidnr" and "strasse".
+func normalizeColumnName(name string) string {
+	var b strings.Builder
+	for _, r := range strings.ToLower(name) {
+		switch r {
+		case 'ä':
+			b.WriteString("ae")
+		case 'ö':
+			b.WriteString("oe")
+		case 'ü':
+			b.WriteString("ue")
+		case 'ß':
+			b.WriteString("ss")
+		default:
+			if unicode.IsLetter(r) || unicode.IsDigit(r) {
+				b.WriteRune(r)
+			}
+		}
+	}
+	return b.String()
+}
+
+// mapTableColumns returns the struct field name for every column of header
+// or an empty string for columns that are not mapped to a field.
+// Columns are matched by the field names of structType, the
+// alternative column names per field name of defaults,
+// and the column names of custom which take precedence.
+// Every field name is only mapped to the first matching column.
+func mapTableColumns(header []string, structType reflect.Type, defaults map[string][]string, custom map[string]string) ([]string, error) {
+	columnFields := make(map[string]string)
+	for fieldName, columns := range defaults {
+		for _, column := range columns {
+			columnFields[normalizeColumnName(column)] = fieldName
+		}
+	}
+	for _, field := range reflect.VisibleFields(structType) {
+		if field.IsExported() && !field.Anonymous {
+			columnFields[normalizeColumnName(field.Name)] = field.Name
+		}
+	}
+	for column, fieldName := range custom {
+		if _, ok := structType.FieldByName(fieldName); !ok {
+			return nil, fmt.Errorf("column %q mapped to unknown %s field %q", column, structType.Name(), fieldName)
+		}
+		columnFields[normalizeColumnName(column)] = fieldName
+	}
+
+	fields := make([]string, len(header))
+	mapped := make(map[string]bool)
+	for i, column := range header {
+		fieldName := columnFields[normalizeColumnName(column)]
+		if fieldName == "" || mapped[fieldName] {
+			continue
+		}
+		fields[i] = fieldName
+		mapped[fieldName] = true
+	}
+	return fields, nil
+}
+
+// errSkipTableRow is returned by the finalize function
+// of readTableRows to skip a row without error
+var errSkipTableRow = errors.New("skip table row")
+
+// readTableRows converts the rows to structs of type T
+// by setting the fields of T mapped to the columns of header.
+// After setting the fields of a row, finalize is called
+// to normalize and validate the struct
+// or to skip the row by returning errSkipTableRow.
+// Rows with errors are not returned as structs
+// but as RowError. Empty rows are skipped.
+func readTableRows[T any](header []string, rows []tableRow, defaults map[string][]string, profile *ColumnProfile, custom map[string]string, finalize func(*T) error) (items []*T, rowErrs []*RowError, err error) {
+	structType := reflect.TypeFor[T]()
+	custom = profile.columns(structType, custom)
+	fields, err := mapTableColumns(header, structType, defaults, custom)
+	if err != nil {
+		return nil, nil, err
+	}
+	if !hasMappedColumn(fields) {
+		return nil, nil, fmt.Errorf("no columns of the header %q could be mapped to %s fields", header, structType.Name())
+	}
+
+	for _, row := range rows {
+		if isEmptyTableRow(row.Values) {
+			continue
+		}
+		item := new(T)
+		var errs []error
+		for i, value := range row.Values {
+			if i >= len(fields) || fields[i] == "" {
+				continue
+			}
+			field := reflect.ValueOf(item).Elem().FieldByName(fields[i])
+			if e := setTableField(field, value); e != nil {
+				errs = append(errs, fmt.Errorf("column %q: %w", header[i], e))
+			}
+		}
+		if len(errs) == 0 && finalize != nil {
+			e := finalize(item)
+			if errors.Is(e, errSkipTableRow) {
+				continue
+			}
+			if e != nil {
+				errs = append(errs, e)
+			}
+		}
+		if len(errs) > 0 {
+			rowErrs = append(rowErrs, &RowError{Line: row.Line, Err: errors.Join(errs...)})
+			continue
+		}
+		items = append(items, item)
+	}
+	return items, rowErrs, nil
+}
+
+func hasMappedColumn(fields []string) bool {
+	for _, f := range fields {
+		if f != "" {
+			return true
+		}
+	}
+	return false
+}
+
+func isEmptyTableRow(values []string) bool {
+	for _, v := range values {
+		if strings.TrimSpace(v) != "" {
+			return false
+		}
+	}
+	return true
+}
+
+// setTableField sets field from the string value of a table cell.
+// String kinds are set directly, empty strings are null for nullable types.
+// Multiple values for string slices are separated by "|" or newlines.
+func setTableField(field reflect.Value, value string) error {
+	value = strings.TrimSpace(value)
+	switch field.Kind() {
+	case reflect.String:
+		field.SetString(value)
+		return nil
+
+	case reflect.Bool:
+		b, err := parseTableBool(value)
+		if err != nil {
+			return err
+		}
+		field.SetBool(b)
+		return nil
+
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if value == "" {
+			field.SetInt(0)
+			return nil
+		}
+		i, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return err
+		}
+		field.SetInt(i)
+		return nil
+
+	case reflect.Slice:
+		if field.Type().Elem().Kind() != reflect.String {
+			break
+		}
+		values := strings.FieldsFunc(value, func(r rune) bool { return r == '|' || r == '\n' })
+		if len(values) == 0 {
+			field.SetZero()
+			return nil
+		}
+		slice := reflect.MakeSlice(field.Type(), 0, len(values))
+		for _, v := range values {
+			if v = strings.TrimSpace(v); v != "" {
+				slice = reflect.Append(slice, reflect.ValueOf(v).Convert(field.Type().Elem()))
+			}
+		}
+		field.Set(slice)
+		return nil
+	}
+	return fmt.Errorf("can't set field of type %s from table cell", field.Type())
+}
+
+func parseTableBool(value string) (bool, error) {
+	switch strings.ToLower(value) {
+	case "", "0", "false", "no", "n", "nein", "falsch", "inaktiv", "inactive":
+		return false, nil
+	case "1", "true", "yes", "y", "ja", "j", "x", "wahr", "aktiv", "active":
+		return true, nil
+	}
+	return false, fmt.Errorf("invalid boolean value %q", value)
+}
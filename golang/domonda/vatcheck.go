@@ -0,0 +1,262 @@
+package domonda
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/domonda/go-types/vat"
+
+	"github.com/domonda/api/golang/domonda/partnername"
+)
+
+// VIESURL is the base URL of the REST API of the
+// VAT Information Exchange System (VIES) of the European Commission
+const VIESURL = "https://ec.europa.eu/taxation_customs/vies/rest-api"
+
+// VATCheckResult is the result of a VAT ID verification
+type VATCheckResult struct {
+	// VATID that was checked
+	VATID vat.ID
+
+	// Valid is true if the VAT ID is registered and active
+	Valid bool
+
+	// Name is the registered name of the company
+	// or empty if not disclosed by the member state
+	Name string
+
+	// Address is the registered address of the company
+	// or empty if not disclosed by the member state
+	Address string
+
+	// CheckedAt is the time of the check
+	CheckedAt time.Time
+}
+
+// VATChecker verifies VAT IDs with a registry like VIES
+type VATChecker interface {
+	// CheckVATID returns the registration of vatID.
+	// An error is returned if the check could not be performed,
+	// not if the VAT ID is invalid.
+	CheckVATID(ctx context.Context, vatID vat.ID) (*VATCheckResult, error)
+}
+
+// VIESChecker is a VATChecker using the VIES REST API.
+type VIESChecker struct {
+	// URL of the VIES REST API, VIESURL is used if empty.
+	// Can be set to a local stand-in for testing.
+	URL string
+
+	// Client for the requests, http.DefaultClient is used if nil
+	Client *http.Client
+}
+
+// CheckVATID implements the VATChecker interface
+func (c *VIESChecker) CheckVATID(ctx context.Context, vatID vat.ID) (*VATCheckResult, error) {
+	vatID, err := vatID.Normalized()
+	if err != nil {
+		return nil, err
+	}
+	if vatID.IsMOSS() {
+		return nil, fmt.Errorf("MOSS VAT ID %s can't be checked with VIES", vatID)
+	}
+	payload, err := json.Marshal(map[string]string{
+		"countryCode": string(vatID[:2]),
+		"vatNumber":   vatID.Number(),
+	})
+	if err != nil {
+		return nil, err
+	}
+	url := c.URL
+	if url == "" {
+		url = VIESURL
+	}
+	request, err := http.NewRequestWithContext(ctx, "POST", strings.TrimSuffix(url, "/")+"/check-vat-number", bytes.NewReader(payload))
+	if err != nil {
+		return nil, err
+	}
+	request.Header.Set("Content-Type", "application/json")
+	request.Header.Set("Accept", "application/json")
+	client := c.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	response, err := client.Do(request)
+	if err != nil {
+		return nil, err
+	}
+	defer response.Body.Close()
+	data, err := io.ReadAll(response.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response body: %w", err)
+	}
+	if response.StatusCode != 200 {
+		return nil, fmt.Errorf("unexpected status code: %d, body: %s", response.StatusCode, data)
+	}
+	var result struct {
+		Valid     bool   `json:"valid"`
+		Name      string `json:"name"`
+		Address   string `json:"address"`
+		UserError string `json:"userError"`
+	}
+	if err := json.Unmarshal(data, &result); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal response body: %w", err)
+	}
+	if result.UserError != "" && result.UserError != "VALID" && result.UserError != "INVALID" {
+		// Errors like MS_UNAVAILABLE or TIMEOUT
+		return nil, fmt.Errorf("VIES error: %s", result.UserError)
+	}
+	return &VATCheckResult{
+		VATID:     vatID,
+		Valid:     result.Valid,
+		Name:      viesValue(result.Name),
+		Address:   viesValue(result.Address),
+		CheckedAt: time.Now(),
+	}, nil
+}
+
+// viesValue returns the trimmed value or an empty string
+// for the placeholder "---" of undisclosed values
+func viesValue(s string) string {
+	s = strings.TrimSpace(s)
+	if s == "---" {
+		return ""
+	}
+	return s
+}
+
+// NewCachingVATChecker returns a VATChecker that caches
+// the results of checker for the duration ttl.
+// Errors are not cached.
+func NewCachingVATChecker(checker VATChecker, ttl time.Duration) VATChecker {
+	return &cachingVATChecker{
+		checker: checker,
+		ttl:     ttl,
+		cache:   make(map[vat.ID]*VATCheckResult),
+	}
+}
+
+type cachingVATChecker struct {
+	checker VATChecker
+	ttl     time.Duration
+	mtx     sync.Mutex
+	cache   map[vat.ID]*VATCheckResult
+}
+
+func (c *cachingVATChecker) CheckVATID(ctx context.Context, vatID vat.ID) (*VATCheckResult, error) {
+	if norm, err := vatID.Normalized(); err == nil {
+		vatID = norm
+	}
+	c.mtx.Lock()
+	result, ok := c.cache[vatID]
+	c.mtx.Unlock()
+	if ok && time.Since(result.CheckedAt) < c.ttl {
+		return result, nil
+	}
+	result, err := c.checker.CheckVATID(ctx, vatID)
+	if err != nil {
+		return nil, err
+	}
+	c.mtx.Lock()
+	c.cache[vatID] = result
+	c.mtx.Unlock()
+	return result, nil
+}
+
+// VATNameMinSimilarity is the minimum partnername.Similarity
+// between a Partner.Name and the registered name of its VAT ID
+// below which CheckPartnerVATID returns a warning
+const VATNameMinSimilarity = 0.6
+
+// CheckPartnerVATID checks the VATIDNo of the partner with checker
+// and returns warnings if the VAT ID is invalid or if the registered name
+// differs from the Name and AlternativeNames of the partner,
+// see VATNameMinSimilarity.
+// No warnings are returned for partners without VATIDNo.
+func CheckPartnerVATID(ctx context.Context, checker VATChecker, p *Partner) (warnings []string, err error) {
+	if p.VATIDNo.IsNull() || p.VATIDNo.Get().IsMOSS() {
+		return nil, nil
+	}
+	result, err := checker.CheckVATID(ctx, p.VATIDNo.Get())
+	if err != nil {
+		return nil, fmt.Errorf("VATIDNo '%s' could not be checked: %w", p.VATIDNo, err)
+	}
+	if !result.Valid {
+		return []string{fmt.Sprintf("VATIDNo '%s' is not registered", p.VATIDNo)}, nil
+	}
+	if result.Name != "" {
+		names := append([]string{string(p.Name)}, p.AlternativeNames...)
+		if i, _ := partnername.BestMatch(result.Name, names, VATNameMinSimilarity); i < 0 {
+			warnings = append(warnings, fmt.Sprintf("VATIDNo '%s' is registered for '%s' instead of '%s'", p.VATIDNo, result.Name, p.Name))
+		}
+	}
+	return warnings, nil
+}
+
+// PartnerRuleVATCheck returns a rule that checks VATIDNo with checker
+// before posting the partners with PostPartners.
+// It returns the warnings of CheckPartnerVATID as *FieldError warnings.
+// If resetInvalid is true, a VAT ID that is not registered is set to null
+// and returned as error instead of a warning.
+// Failed checks like an unavailable registry are returned as warnings
+// so that the partners can still be posted.
+//
+// Example:
+//
+//	errs := partner.NormalizeWithRules(true, domonda.PartnerRuleVATCheck(ctx, checker))
+func PartnerRuleVATCheck(ctx context.Context, checker VATChecker) PartnerRule {
+	return func(p *Partner, resetInvalid bool) []error {
+		if !p.VATIDNo.ValidAndNotNull() || p.VATIDNo.Get().IsMOSS() {
+			return nil
+		}
+		result, err := checker.CheckVATID(ctx, p.VATIDNo.Get())
+		if err != nil {
+			return []error{fieldWarning("VATIDNo", fmt.Errorf("VATIDNo '%s' could not be checked: %w", p.VATIDNo, err))}
+		}
+		if !result.Valid {
+			err := fieldErrorf("VATIDNo", "VATIDNo '%s' is not registered", p.VATIDNo)
+			if resetInvalid {
+				p.VATIDNo.SetNull()
+				return []error{err}
+			}
+			err.Warning = true
+			return []error{err}
+		}
+		if result.Name != "" {
+			names := append([]string{string(p.Name)}, p.AlternativeNames...)
+			if i, _ := partnername.BestMatch(result.Name, names, VATNameMinSimilarity); i < 0 {
+				return []error{fieldWarning("VATIDNo", fmt.Errorf("VATIDNo '%s' is registered for '%s' instead of '%s'", p.VATIDNo, result.Name, p.Name))}
+			}
+		}
+		return nil
+	}
+}
+
+// CheckPartnerResultsVATIDs is an optional step after PostPartners
+// that appends the warnings of CheckPartnerVATID
+// for the NormalizedInput of every result to its InputWarnings.
+// Errors of the checks are returned joined
+// after all results have been checked.
+func CheckPartnerResultsVATIDs(ctx context.Context, checker VATChecker, results []ImportPartnerResult) error {
+	var errs []error
+	for i := range results {
+		if results[i].NormalizedInput == nil {
+			continue
+		}
+		warnings, err := CheckPartnerVATID(ctx, checker, results[i].NormalizedInput)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		results[i].InputWarnings = append(results[i].InputWarnings, warnings...)
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,154 @@
+package domonda
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/domonda/go-types/vat"
+)
+
+// newVIESStandIn returns a local stand-in for the VIES REST API
+// with the registered names of VAT numbers.
+// Unknown VAT numbers are not registered and the VAT number
+// "U12345675" returns the VIES error MS_UNAVAILABLE.
+func newVIESStandIn(t *testing.T, names map[string]string, requests *atomic.Int32) *httptest.Server {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		if r.Method != http.MethodPost || r.URL.Path != "/check-vat-number" {
+			http.NotFound(w, r)
+			return
+		}
+		var request struct {
+			CountryCode string `json:"countryCode"`
+			VATNumber   string `json:"vatNumber"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		response := map[string]any{"countryCode": request.CountryCode, "vatNumber": request.VATNumber}
+		name, registered := names[request.CountryCode+request.VATNumber]
+		switch {
+		case request.VATNumber == "U12345675":
+			response["userError"] = "MS_UNAVAILABLE"
+		case registered:
+			response["valid"] = true
+			response["userError"] = "VALID"
+			response["name"] = name
+			response["address"] = "---"
+		default:
+			response["valid"] = false
+			response["userError"] = "INVALID"
+			response["name"] = "---"
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(response)
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestVIESChecker(t *testing.T) {
+	var requests atomic.Int32
+	server := newVIESStandIn(t, map[string]string{"ATU13585627": "Muster GmbH"}, &requests)
+	checker := &VIESChecker{URL: server.URL}
+	ctx := context.Background()
+
+	result, err := checker.CheckVATID(ctx, "ATU 135 85 627")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !result.Valid || result.VATID != "ATU13585627" || result.Name != "Muster GmbH" || result.Address != "" {
+		t.Errorf("unexpected result %+v", result)
+	}
+
+	result, err = checker.CheckVATID(ctx, "DE129273398")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Valid || result.Name != "" {
+		t.Errorf("unexpected result %+v", result)
+	}
+
+	if _, err := checker.CheckVATID(ctx, "ATU12345675"); err == nil {
+		t.Error("expected error for VIES error MS_UNAVAILABLE")
+	}
+	if _, err := (&VIESChecker{URL: server.URL + "/unknown"}).CheckVATID(ctx, "DE129273398"); err == nil {
+		t.Error("expected error for status code 404")
+	}
+}
+
+func TestCachingVATChecker(t *testing.T) {
+	var requests atomic.Int32
+	server := newVIESStandIn(t, map[string]string{"ATU13585627": "Muster GmbH"}, &requests)
+	checker := NewCachingVATChecker(&VIESChecker{URL: server.URL}, time.Hour)
+	ctx := context.Background()
+
+	for _, vatID := range []vat.ID{"ATU13585627", "atu 13585627", "ATU13585627"} {
+		if _, err := checker.CheckVATID(ctx, vatID); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if n := requests.Load(); n != 1 {
+		t.Errorf("expected 1 request but got %d", n)
+	}
+	// Errors are not cached
+	for range 2 {
+		if _, err := checker.CheckVATID(ctx, "ATU12345675"); err == nil {
+			t.Error("expected error")
+		}
+	}
+	if n := requests.Load(); n != 3 {
+		t.Errorf("expected 3 requests but got %d", n)
+	}
+}
+
+func TestPartnerRuleVATCheck(t *testing.T) {
+	var requests atomic.Int32
+	server := newVIESStandIn(t, map[string]string{
+		"ATU13585627": "MUSTER GMBH",
+		"DE129273398": "Other Company AG",
+	}, &requests)
+	rule := PartnerRuleVATCheck(context.Background(), &VIESChecker{URL: server.URL})
+
+	for _, tt := range []struct {
+		name         string
+		vatID        vat.NullableID
+		resetInvalid bool
+		wantErr      bool
+		wantWarning  bool
+		wantVATID    vat.NullableID
+	}{
+		{name: "registered", vatID: "ATU13585627", wantVATID: "ATU13585627"},
+		{name: "no VAT ID"},
+		{name: "different name", vatID: "DE129273398", wantWarning: true, wantVATID: "DE129273398"},
+		{name: "not registered", vatID: "DE136695976", wantWarning: true, wantVATID: "DE136695976"},
+		{name: "not registered reset", vatID: "DE136695976", resetInvalid: true, wantErr: true},
+		{name: "check failed", vatID: "ATU12345675", resetInvalid: true, wantWarning: true, wantVATID: "ATU12345675"},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			p := &Partner{VATIDNo: tt.vatID}
+			p.Name.Set("Muster GmbH")
+			errs := rule(p, tt.resetInvalid)
+			switch {
+			case tt.wantErr || tt.wantWarning:
+				if len(errs) != 1 || IsWarning(errs[0]) != tt.wantWarning {
+					t.Fatalf("expected one error with warning %t but got %v", tt.wantWarning, errs)
+				}
+				if fieldErr, ok := errs[0].(*FieldError); !ok || fieldErr.Field != "VATIDNo" {
+					t.Errorf("expected *FieldError of VATIDNo but got %#v", errs[0])
+				}
+			case len(errs) > 0:
+				t.Fatalf("unexpected errors: %v", errs)
+			}
+			if p.VATIDNo != tt.wantVATID {
+				t.Errorf("VATIDNo = %q, expected %q", p.VATIDNo, tt.wantVATID)
+			}
+		})
+	}
+}
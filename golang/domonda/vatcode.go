@@ -0,0 +1,238 @@
+package domonda
+
+//go:generate go tool go-enum $GOFILE
+//go:generate cp ../../vat-codes-and-percentages.csv vat-codes-and-percentages.csv
+
+import (
+	"bytes"
+	_ "embed"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/domonda/go-types/country"
+	"github.com/domonda/go-types/date"
+	"github.com/domonda/go-types/uu"
+)
+
+//go:embed vat-codes-and-percentages.csv
+var vatCodesCSV []byte
+
+// VATCode is a VAT code of the domonda VAT code catalogue
+// that can be referenced by AccountingItem.ValueAddedTaxID.
+// See vat-codes-and-percentages.csv in the root of the repository.
+type VATCode struct {
+	// ID of the VAT code used as AccountingItem.ValueAddedTaxID
+	ID uu.ID
+
+	// Country of the VAT code
+	Country country.Code
+
+	// Name of the VAT code like "Vorsteuer"
+	Name string
+
+	// ShortName of the VAT code like "VST"
+	ShortName string
+
+	// Type is RECLAIMABLE for input tax or PAYABLE for output tax
+	Type VATCodeType
+
+	// NetOnly is true if only the net amount is booked
+	NetOnly bool
+
+	// VATIDRequired is true if the partner must have a VAT ID
+	VATIDRequired bool
+
+	// Percents are the VAT percentages of the code
+	Percents []float64
+
+	// Codes are the codes of the VAT code in accounting systems
+	Codes []*VATSystemCode
+
+	// IntroducedAt is the date from which the code is valid
+	IntroducedAt date.NullableDate
+
+	// ExpiredAt is the date until which the code is valid
+	ExpiredAt date.NullableDate
+}
+
+// VATSystemCode is the code of a VAT code in an accounting system
+type VATSystemCode struct {
+	// System is the accounting system like "DATEV", "BMD", "DVO", or "RZL"
+	System string
+
+	// Code in the accounting system like "9r" or "1/28"
+	Code string
+
+	// Percent is the VAT percentage the code is limited to
+	// or nil if the code is used for all percentages
+	Percent *float64
+}
+
+// SystemCode returns the code of the accounting system for the VAT percentage.
+// Codes that are limited to another percentage are ignored,
+// percent can be nil to ignore the percentages of the codes.
+// The first matching code is returned or nil if there is none.
+func (c *VATCode) SystemCode(system string, percent *float64) *VATSystemCode {
+	for _, code := range c.Codes {
+		if code.System != system {
+			continue
+		}
+		if percent != nil && code.Percent != nil && *percent != *code.Percent {
+			continue
+		}
+		return code
+	}
+	return nil
+}
+
+// VATCodeType is the type of a VAT code.
+type VATCodeType string //#enum
+
+const (
+	// VATCodeTypeReclaimable is input tax that can be reclaimed
+	VATCodeTypeReclaimable VATCodeType = "RECLAIMABLE"
+
+	// VATCodeTypePayable is output tax that has to be paid
+	VATCodeTypePayable VATCodeType = "PAYABLE"
+)
+
+// Valid indicates if v is any of the valid values for VATCodeType
+func (v VATCodeType) Valid() bool {
+	switch v {
+	case
+		VATCodeTypeReclaimable,
+		VATCodeTypePayable:
+		return true
+	}
+	return false
+}
+
+// Validate returns an error if v is none of the valid values for VATCodeType
+func (v VATCodeType) Validate() error {
+	if !v.Valid() {
+		return fmt.Errorf("invalid value %#v for type domonda.VATCodeType", v)
+	}
+	return nil
+}
+
+// Enums returns all valid values for VATCodeType
+func (VATCodeType) Enums() []VATCodeType {
+	return []VATCodeType{
+		VATCodeTypeReclaimable,
+		VATCodeTypePayable,
+	}
+}
+
+// EnumStrings returns all valid values for VATCodeType as strings
+func (VATCodeType) EnumStrings() []string {
+	return []string{
+		"RECLAIMABLE",
+		"PAYABLE",
+	}
+}
+
+// String implements the fmt.Stringer interface for VATCodeType
+func (v VATCodeType) String() string {
+	return string(v)
+}
+
+var vatCodes = sync.OnceValues(func() ([]*VATCode, error) {
+	return parseVATCodesCSV(vatCodesCSV)
+})
+
+// VATCodes returns the VAT code catalogue
+// embedded from vat-codes-and-percentages.csv.
+// The returned VAT codes must not be modified.
+func VATCodes() ([]*VATCode, error) {
+	return vatCodes()
+}
+
+// VATCodeByID returns the VAT code with the passed ID
+// from the catalogue returned by VATCodes or nil if not found.
+func VATCodeByID(id uu.ID) *VATCode {
+	codes, _ := vatCodes()
+	for _, code := range codes {
+		if code.ID == id {
+			return code
+		}
+	}
+	return nil
+}
+
+func parseVATCodesCSV(data []byte) ([]*VATCode, error) {
+	records, err := csv.NewReader(bytes.NewReader(data)).ReadAll()
+	if err != nil {
+		return nil, err
+	}
+	if len(records) == 0 {
+		return nil, errors.New("empty VAT codes CSV")
+	}
+	var (
+		codes []*VATCode
+		errs  []error
+	)
+	for i, record := range records[1:] {
+		code, err := parseVATCodeRecord(record)
+		if err != nil {
+			errs = append(errs, &RowError{Line: i + 2, Err: err})
+			continue
+		}
+		codes = append(codes, code)
+	}
+	return codes, errors.Join(errs...)
+}
+
+// parseVATCodeRecord parses a record with the columns
+// ID, Country, Name, Short Name, Type, Net Only Amount, VATIDRequired,
+// Percents, Codes, Introduced At, Expired At
+func parseVATCodeRecord(record []string) (*VATCode, error) {
+	if len(record) != 11 {
+		return nil, fmt.Errorf("expected 11 columns but got %d", len(record))
+	}
+	id, err := uu.IDFromString(record[0])
+	if err != nil {
+		return nil, err
+	}
+	code := &VATCode{
+		ID:            id,
+		Country:       country.Code(record[1]),
+		Name:          record[2],
+		ShortName:     record[3],
+		Type:          VATCodeType(record[4]),
+		NetOnly:       record[5] == "NET ONLY",
+		VATIDRequired: record[6] == "VAT-ID",
+		IntroducedAt:  date.NullableDate(record[9]),
+		ExpiredAt:     date.NullableDate(record[10]),
+	}
+	if err = code.Type.Validate(); err != nil {
+		return nil, err
+	}
+	if record[7] != "" {
+		if err = json.Unmarshal([]byte(record[7]), &code.Percents); err != nil {
+			return nil, fmt.Errorf("invalid percents %q: %w", record[7], err)
+		}
+	}
+	for line := range strings.SplitSeq(record[8], "\n") {
+		system, value, _ := strings.Cut(line, ":")
+		system, value = strings.TrimSpace(system), strings.TrimSpace(value)
+		if system == "" || value == "" {
+			continue
+		}
+		systemCode := &VATSystemCode{System: system, Code: value}
+		if c, p, ok := strings.Cut(value, " "); ok && strings.HasSuffix(p, "%") {
+			percent, err := strconv.ParseFloat(strings.TrimSuffix(p, "%"), 64)
+			if err != nil {
+				return nil, fmt.Errorf("invalid %s code %q: %w", system, value, err)
+			}
+			systemCode.Code = c
+			systemCode.Percent = &percent
+		}
+		code.Codes = append(code.Codes, systemCode)
+	}
+	return code, nil
+}
@@ -0,0 +1,247 @@
+package domonda
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"path"
+	"strconv"
+	"strings"
+)
+
+// XLSXConfig configures how master data is read from XLSX files.
+// The zero value reads the first worksheet
+// and uses the default column names.
+type XLSXConfig struct {
+	// Sheet is the name of the worksheet to read
+	// or empty to read the first worksheet
+	Sheet string
+
+	// Profile is an optional column mapping of an accounting system
+	// like ColumnProfileBMD that takes precedence over the default column names
+	Profile *ColumnProfile
+
+	// Columns maps header names to struct field names.
+	// Header names are matched case-insensitive ignoring whitespace
+	// and punctuation and take precedence over Profile
+	// and the default column names.
+	Columns map[string]string
+}
+
+// readXLSX reads the items of type T from XLSX data
+// using the columns of config and defaults.
+// See readTableRows for finalize.
+func readXLSX[T any](r io.Reader, config *XLSXConfig, defaults map[string][]string, finalize func(*T) error) (items []*T, rowErrs []*RowError, err error) {
+	if config == nil {
+		config = new(XLSXConfig)
+	}
+	header, rows, err := readXLSXTable(r, config.Sheet)
+	if err != nil {
+		return nil, nil, err
+	}
+	return readTableRows(header, rows, defaults, config.Profile, config.Columns, finalize)
+}
+
+// readXLSXTable reads the first non empty row as header
+// and the following rows as data rows from a worksheet of XLSX data.
+// Only the cell values are read, formulas are not evaluated.
+func readXLSXTable(r io.Reader, sheet string) (header []string, rows []tableRow, err error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, nil, err
+	}
+	archive, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, nil, fmt.Errorf("can't read XLSX: %w", err)
+	}
+	sheetPath, err := xlsxSheetPath(archive, sheet)
+	if err != nil {
+		return nil, nil, err
+	}
+	sharedStrings, err := xlsxSharedStrings(archive)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var worksheet struct {
+		Rows []struct {
+			Num   int `xml:"r,attr"`
+			Cells []struct {
+				Ref       string `xml:"r,attr"`
+				Type      string `xml:"t,attr"`
+				Value     string `xml:"v"`
+				InlineStr struct {
+					Text string `xml:"t"`
+				} `xml:"is"`
+			} `xml:"c"`
+		} `xml:"sheetData>row"`
+	}
+	err = xlsxUnmarshal(archive, sheetPath, &worksheet)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	for i, row := range worksheet.Rows {
+		line := row.Num
+		if line == 0 {
+			line = i + 1
+		}
+		var values []string
+		for j, cell := range row.Cells {
+			col := j
+			if cell.Ref != "" {
+				col, err = xlsxColumnIndex(cell.Ref)
+				if err != nil {
+					return nil, nil, err
+				}
+			}
+			var value string
+			switch cell.Type {
+			case "s":
+				index, err := strconv.Atoi(cell.Value)
+				if err != nil || index < 0 || index >= len(sharedStrings) {
+					return nil, nil, fmt.Errorf("invalid shared string index %q in XLSX cell %s", cell.Value, cell.Ref)
+				}
+				value = sharedStrings[index]
+			case "inlineStr":
+				value = cell.InlineStr.Text
+			case "b":
+				value = strconv.FormatBool(cell.Value == "1")
+			default:
+				value = cell.Value
+			}
+			for len(values) <= col {
+				values = append(values, "")
+			}
+			values[col] = value
+		}
+		if header == nil {
+			if !isEmptyTableRow(values) {
+				header = values
+			}
+			continue
+		}
+		rows = append(rows, tableRow{Line: line, Values: values})
+	}
+	if header == nil {
+		return nil, nil, errors.New("XLSX worksheet has no header row")
+	}
+	return header, rows, nil
+}
+
+// xlsxSheetPath returns the path within the archive
+// of the worksheet with the passed name or of the first worksheet
+// if name is empty.
+func xlsxSheetPath(archive *zip.Reader, name string) (string, error) {
+	var workbook struct {
+		Sheets []struct {
+			Name  string `xml:"name,attr"`
+			RelID string `xml:"http://schemas.openxmlformats.org/officeDocument/2006/relationships id,attr"`
+		} `xml:"sheets>sheet"`
+	}
+	err := xlsxUnmarshal(archive, "xl/workbook.xml", &workbook)
+	if err != nil {
+		return "", err
+	}
+	var relID string
+	for _, sheet := range workbook.Sheets {
+		if name == "" || sheet.Name == name {
+			relID = sheet.RelID
+			break
+		}
+	}
+	if relID == "" {
+		if name == "" {
+			return "", errors.New("XLSX has no worksheet")
+		}
+		return "", fmt.Errorf("XLSX has no worksheet named %q", name)
+	}
+
+	var relationships struct {
+		Relationships []struct {
+			ID     string `xml:"Id,attr"`
+			Target string `xml:"Target,attr"`
+		} `xml:"Relationship"`
+	}
+	err = xlsxUnmarshal(archive, "xl/_rels/workbook.xml.rels", &relationships)
+	if err != nil {
+		return "", err
+	}
+	for _, rel := range relationships.Relationships {
+		if rel.ID != relID {
+			continue
+		}
+		if strings.HasPrefix(rel.Target, "/") {
+			return strings.TrimPrefix(rel.Target, "/"), nil
+		}
+		return path.Join("xl", rel.Target), nil
+	}
+	return "", fmt.Errorf("XLSX worksheet relationship %q not found", relID)
+}
+
+// xlsxSharedStrings returns the shared strings table
+// of the archive which is optional.
+func xlsxSharedStrings(archive *zip.Reader) ([]string, error) {
+	var sst struct {
+		Items []struct {
+			Text string `xml:"t"`
+			Runs []struct {
+				Text string `xml:"t"`
+			} `xml:"r"`
+		} `xml:"si"`
+	}
+	err := xlsxUnmarshal(archive, "xl/sharedStrings.xml", &sst)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	strs := make([]string, len(sst.Items))
+	for i, item := range sst.Items {
+		if len(item.Runs) == 0 {
+			strs[i] = item.Text
+			continue
+		}
+		var b strings.Builder
+		for _, run := range item.Runs {
+			b.WriteString(run.Text)
+		}
+		strs[i] = b.String()
+	}
+	return strs, nil
+}
+
+func xlsxUnmarshal(archive *zip.Reader, name string, v any) error {
+	file, err := archive.Open(name)
+	if err != nil {
+		return fmt.Errorf("can't open %s in XLSX: %w", name, err)
+	}
+	defer file.Close()
+	err = xml.NewDecoder(file).Decode(v)
+	if err != nil {
+		return fmt.Errorf("can't parse %s in XLSX: %w", name, err)
+	}
+	return nil
+}
+
+// xlsxColumnIndex returns the 0-based column index
+// of a cell reference like "B7" or "AA12".
+func xlsxColumnIndex(ref string) (int, error) {
+	col := 0
+	for i, r := range ref {
+		switch {
+		case r >= 'A' && r <= 'Z':
+			col = col*26 + int(r-'A') + 1
+		case r >= '0' && r <= '9' && i > 0:
+			return col - 1, nil
+		default:
+			return 0, fmt.Errorf("invalid XLSX cell reference %q", ref)
+		}
+	}
+	return 0, fmt.Errorf("invalid XLSX cell reference %q", ref)
+}